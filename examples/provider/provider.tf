@@ -1,5 +1,30 @@
 provider "awsrdsdata" {
   region = "us-east-1" # optional
+
+  # optional, refuse to manage clusters of any other AWS account
+  allowed_account_ids = ["123456789012"]
+
+  # optional, never change databases (e.g. in plan-only pipelines)
+  read_only = false
+
+  # optional, show the SQL statements of every change in the plan output
+  sql_preview = true
+
+  # optional, check connections and privileges when planning
+  validate_on_plan = true
+
+  # optional, guardrails evaluated when planning every resource
+  policy {
+    protected_accounts   = ["admin", "/^ops_.*$/"]
+    forbidden_privileges = ["SUPER", "GRANT OPTION", "ALL ON *.*"]
+  }
+
+  # optional, record every statement run into a JSON lines file
+  audit_log {
+    path           = "${path.root}/rdsdata-audit.jsonl"
+    workspace      = terraform.workspace
+    tag_statements = true
+  }
 }
 
 # Provision credentials for the master DB acount
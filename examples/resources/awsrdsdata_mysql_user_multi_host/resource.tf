@@ -0,0 +1,19 @@
+# Provision credentials for the MySQL DB acount used to test the provider
+resource "random_password" "app_account_password" {
+  length  = 16
+  special = false
+}
+
+# One `app` account per host pattern, sharing the same password and privileges
+resource "awsrdsdata_mysql_user_multi_host" "app_account" {
+  user                  = "app"
+  hosts                 = ["10.1.%", "10.2.%", "localhost"]
+  password              = random_password.app_account_password.result
+  database_resource_arn = "<YOUR_MYSQL_RDS_CLUSTER_ARN_HERE>"
+  database_secret_arn   = "<YOUR_MYSQL_RDS_CLUSTER_MASTER_CREDENTIALS_AWS_SECRET_ARN_HERE>"
+
+  grant {
+    database   = "<YOUR_MYSQL_DATABASE_NAME_HERE>"
+    privileges = ["SELECT", "INSERT", "UPDATE"]
+  }
+}
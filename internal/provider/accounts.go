@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// checkAccountId ensures the AWS account is allowed by the provider `allowed_account_ids` and `forbidden_account_ids`.
+func (d *RdsDataProviderData) checkAccountId(accountId string) error {
+	if slices.Contains(d.ForbiddenAccountIds, accountId) {
+		return fmt.Errorf("the AWS account %s is listed in the provider `forbidden_account_ids`", accountId)
+	}
+
+	if len(d.AllowedAccountIds) > 0 && !slices.Contains(d.AllowedAccountIds, accountId) {
+		return fmt.Errorf("the AWS account %s is not listed in the provider `allowed_account_ids`", accountId)
+	}
+
+	return nil
+}
+
+// checkAccounts reports whether the provider restricts the AWS accounts it sends requests to.
+func (d *RdsDataProviderData) checkAccounts() bool {
+	return len(d.AllowedAccountIds) > 0 || len(d.ForbiddenAccountIds) > 0
+}
+
+// checkCallerAccount ensures the provider credentials belong to an allowed AWS account, using the STS endpoint of the
+// region (the provider region, or the region of the first ARN a request is sent for when the provider has none).
+// The account is resolved once for the provider lifetime, failed lookups are retried.
+func (d *RdsDataProviderData) checkCallerAccount(ctx context.Context, region string) error {
+	if !d.checkAccounts() {
+		return nil
+	}
+
+	d.callerAccountMutex.Lock()
+	defer d.callerAccountMutex.Unlock()
+
+	if d.callerAccountId == "" {
+		config := d.clients.config.Copy()
+		config.Region = region
+
+		callerIdentity, err := sts.NewFromConfig(config).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return fmt.Errorf(
+				"unable to resolve the AWS account of the provider credentials, required by `allowed_account_ids` "+
+					"and `forbidden_account_ids`: %w", err,
+			)
+		}
+
+		d.callerAccountId = aws.ToString(callerIdentity.Account)
+	}
+
+	if err := d.checkAccountId(d.callerAccountId); err != nil {
+		return fmt.Errorf("the provider credentials are not allowed: %w", err)
+	}
+
+	return nil
+}
+
+// checkConnectionAccounts ensures the database cluster, secret and assumed role of the connection belong to
+// allowed AWS accounts, before any request is sent with it.
+func (d *RdsDataProviderData) checkConnectionAccounts(connection databaseConnection) error {
+	if !d.checkAccounts() {
+		return nil
+	}
+
+	connectionArns := []struct {
+		Kind  arnKind
+		Value string
+	}{
+		{arnKindRdsCluster, connection.ResourceArn},
+		{arnKindSecretsManagerSecret, connection.SecretArn},
+		{arnKindIamRole, connection.AssumeRoleArn},
+	}
+
+	for _, connectionArn := range connectionArns {
+		if connectionArn.Value == "" && connectionArn.Kind == arnKindIamRole {
+			continue
+		}
+
+		parsedArn, err := parseResourceArn(connectionArn.Kind, connectionArn.Value)
+		if err != nil {
+			return err
+		}
+
+		if err := d.checkAccountId(parsedArn.AccountId); err != nil {
+			return fmt.Errorf("%s ARN %s: %w", arnKindDetails[connectionArn.Kind].Description, connectionArn.Value, err)
+		}
+	}
+
+	return nil
+}
+
+// checkArnAccount ensures the resource of the ARN (e.g. a password secret) belongs to an allowed AWS account.
+func (d *RdsDataProviderData) checkArnAccount(value string) error {
+	if !d.checkAccounts() || value == "" {
+		return nil
+	}
+
+	parsedArn, err := arn.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid ARN: %w", value, err)
+	}
+
+	if err := d.checkAccountId(parsedArn.AccountID); err != nil {
+		return fmt.Errorf("ARN %s: %w", value, err)
+	}
+
+	return nil
+}
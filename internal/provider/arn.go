@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the ARN types fully satisfy framework interfaces.
+var (
+	_ basetypes.StringTypable     = ArnType{}
+	_ basetypes.StringValuable    = ArnValue{}
+	_ xattr.ValidateableAttribute = ArnValue{}
+)
+
+// arnKind identifies the AWS resource an ARN attribute refers to.
+type arnKind int
+
+const (
+	// arn:<partition>:rds:<region>:<account>:cluster:<cluster identifier>
+	arnKindRdsCluster arnKind = iota
+	// arn:<partition>:secretsmanager:<region>:<account>:secret:<secret name>
+	arnKindSecretsManagerSecret
+	// arn:<partition>:iam::<account>:role/<role path and name>
+	arnKindIamRole
+)
+
+// arnKindDetails describes the service and resource type expected for each ARN kind.
+var arnKindDetails = map[arnKind]struct {
+	Description  string
+	Service      string
+	ResourceType string
+	Example      string
+	ResourceId   *regexp.Regexp
+	// Global resources (e.g. IAM roles) have no region
+	Global bool
+	// ResourceSeparator separates the resource type from the resource ID (`:` when empty)
+	ResourceSeparator string
+}{
+	arnKindRdsCluster: {
+		Description:  "RDS cluster",
+		Service:      "rds",
+		ResourceType: "cluster",
+		Example:      "arn:aws:rds:us-east-1:123456789012:cluster:my-cluster",
+		ResourceId:   regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*(-[a-zA-Z0-9]+)*$`),
+	},
+	arnKindSecretsManagerSecret: {
+		Description:  "Secrets Manager secret",
+		Service:      "secretsmanager",
+		ResourceType: "secret",
+		Example:      "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret-AbCdEf",
+		ResourceId:   regexp.MustCompile(`^[a-zA-Z0-9/_+=.@\-]{1,512}$`),
+	},
+	arnKindIamRole: {
+		Description:       "IAM role",
+		Service:           "iam",
+		ResourceType:      "role",
+		Example:           "arn:aws:iam::123456789012:role/rdsdata-admin",
+		ResourceId:        regexp.MustCompile(`^([a-zA-Z0-9+=,.@_/\-]+/)?[a-zA-Z0-9+=,.@_\-]{1,64}$`),
+		Global:            true,
+		ResourceSeparator: "/",
+	},
+}
+
+// The region prefixes of each AWS partition (regions of the `aws` partition have none of the other prefixes).
+var arnPartitionRegionPrefixes = map[string]string{
+	"aws":        "",
+	"aws-cn":     "cn-",
+	"aws-us-gov": "us-gov-",
+	"aws-iso":    "us-iso-",
+	"aws-iso-b":  "us-isob-",
+	"aws-iso-e":  "eu-isoe-",
+	"aws-iso-f":  "us-isof-",
+}
+
+var (
+	arnRegionRegex    = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+	arnAccountIdRegex = regexp.MustCompile(`^\d{12}$`)
+)
+
+// resourceArn is a parsed RDS cluster, Secrets Manager secret or IAM role ARN.
+type resourceArn struct {
+	Partition    string
+	Service      string
+	Region       string
+	AccountId    string
+	ResourceType string
+	ResourceId   string
+}
+
+// parseResourceArn parses and validates an ARN of the given kind.
+func parseResourceArn(kind arnKind, value string) (resourceArn, error) {
+	details := arnKindDetails[kind]
+
+	parsed, err := arn.Parse(value)
+	if err != nil {
+		return resourceArn{}, fmt.Errorf("%q is not a valid ARN: %s (e.g. `%s`)", value, err, details.Example)
+	}
+
+	regionPrefix, ok := arnPartitionRegionPrefixes[parsed.Partition]
+	if !ok {
+		partitions := make([]string, 0, len(arnPartitionRegionPrefixes))
+		for partition := range arnPartitionRegionPrefixes {
+			partitions = append(partitions, partition)
+		}
+
+		sort.Strings(partitions)
+
+		return resourceArn{}, fmt.Errorf("%q has an unknown partition %q, expected one of: %s", value, parsed.Partition, strings.Join(partitions, ", "))
+	}
+
+	if parsed.Service != details.Service {
+		return resourceArn{}, fmt.Errorf("%q is an ARN of the %q service, expected an ARN of the %q service (e.g. `%s`)", value, parsed.Service, details.Service, details.Example)
+	}
+
+	switch {
+	case details.Global && parsed.Region != "":
+		return resourceArn{}, fmt.Errorf("%q has the region %q, but %s ARNs have no region (e.g. `%s`)", value, parsed.Region, details.Description, details.Example)
+	case !details.Global && !arnRegionRegex.MatchString(parsed.Region):
+		return resourceArn{}, fmt.Errorf("%q has an invalid region %q (e.g. `us-east-1`)", value, parsed.Region)
+	}
+
+	if parsed.Region != "" && regionPrefix != "" && !strings.HasPrefix(parsed.Region, regionPrefix) {
+		return resourceArn{}, fmt.Errorf("%q has the region %q, which is not part of the %q partition", value, parsed.Region, parsed.Partition)
+	}
+
+	if parsed.Region != "" && regionPrefix == "" {
+		for partition, prefix := range arnPartitionRegionPrefixes {
+			if prefix != "" && strings.HasPrefix(parsed.Region, prefix) {
+				return resourceArn{}, fmt.Errorf("%q has the region %q, which is part of the %q partition, not %q", value, parsed.Region, partition, parsed.Partition)
+			}
+		}
+	}
+
+	if !arnAccountIdRegex.MatchString(parsed.AccountID) {
+		return resourceArn{}, fmt.Errorf("%q has an invalid account ID %q, expected 12 digits", value, parsed.AccountID)
+	}
+
+	resourceSeparator := details.ResourceSeparator
+	if resourceSeparator == "" {
+		resourceSeparator = ":"
+	}
+
+	resourceType, resourceId, _ := strings.Cut(parsed.Resource, resourceSeparator)
+
+	if resourceType != details.ResourceType {
+		return resourceArn{}, fmt.Errorf("%q is an ARN of a %q resource, expected an ARN of a %q resource (e.g. `%s`)", value, resourceType, details.ResourceType, details.Example)
+	}
+
+	if !details.ResourceId.MatchString(resourceId) {
+		return resourceArn{}, fmt.Errorf("%q has an invalid %s identifier %q (e.g. `%s`)", value, details.Description, resourceId, details.Example)
+	}
+
+	return resourceArn{
+		Partition:    parsed.Partition,
+		Service:      parsed.Service,
+		Region:       parsed.Region,
+		AccountId:    parsed.AccountID,
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+	}, nil
+}
+
+// arnRegion returns the region embedded in the ARN, or an empty string when it can't be parsed.
+func arnRegion(value string) string {
+	parsed, err := arn.Parse(value)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Region
+}
+
+// ArnType is the attribute type of RDS cluster, Secrets Manager secret and IAM role ARNs.
+type ArnType struct {
+	basetypes.StringType
+	Kind arnKind
+}
+
+func (t ArnType) String() string {
+	return "ArnType"
+}
+
+func (t ArnType) ValueType(ctx context.Context) attr.Value {
+	return ArnValue{kind: t.Kind}
+}
+
+func (t ArnType) Equal(o attr.Type) bool {
+	other, ok := o.(ArnType)
+	if !ok {
+		return false
+	}
+
+	return t.Kind == other.Kind && t.StringType.Equal(other.StringType)
+}
+
+func (t ArnType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return ArnValue{StringValue: in, kind: t.Kind}, nil
+}
+
+func (t ArnType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+
+	return stringValuable, nil
+}
+
+// ArnValue is an RDS cluster, Secrets Manager secret or IAM role ARN value.
+type ArnValue struct {
+	basetypes.StringValue
+	kind arnKind
+}
+
+func (v ArnValue) Type(ctx context.Context) attr.Type {
+	return ArnType{Kind: v.kind}
+}
+
+func (v ArnValue) Equal(o attr.Value) bool {
+	other, ok := o.(ArnValue)
+	if !ok {
+		return false
+	}
+
+	return v.kind == other.kind && v.StringValue.Equal(other.StringValue)
+}
+
+func (v ArnValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := parseResourceArn(v.kind, v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid "+arnKindDetails[v.kind].Description+" ARN", err.Error())
+	}
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestParseResourceArn(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          arnKind
+		value         string
+		expected      resourceArn
+		expectedError bool
+	}{
+		{
+			name:  "rds cluster",
+			kind:  arnKindRdsCluster,
+			value: "arn:aws:rds:us-east-1:123456789012:cluster:my-cluster",
+			expected: resourceArn{
+				Partition: "aws", Service: "rds", Region: "us-east-1", AccountId: "123456789012",
+				ResourceType: "cluster", ResourceId: "my-cluster",
+			},
+		},
+		{
+			name:  "rds cluster in china",
+			kind:  arnKindRdsCluster,
+			value: "arn:aws-cn:rds:cn-north-1:123456789012:cluster:my-cluster",
+			expected: resourceArn{
+				Partition: "aws-cn", Service: "rds", Region: "cn-north-1", AccountId: "123456789012",
+				ResourceType: "cluster", ResourceId: "my-cluster",
+			},
+		},
+		{
+			name:  "secret in govcloud",
+			kind:  arnKindSecretsManagerSecret,
+			value: "arn:aws-us-gov:secretsmanager:us-gov-west-1:123456789012:secret:rds/admin-AbCdEf",
+			expected: resourceArn{
+				Partition: "aws-us-gov", Service: "secretsmanager", Region: "us-gov-west-1", AccountId: "123456789012",
+				ResourceType: "secret", ResourceId: "rds/admin-AbCdEf",
+			},
+		},
+		{
+			name:  "iam role with a path",
+			kind:  arnKindIamRole,
+			value: "arn:aws:iam::123456789012:role/service/rdsdata-admin",
+			expected: resourceArn{
+				Partition: "aws", Service: "iam", AccountId: "123456789012",
+				ResourceType: "role", ResourceId: "service/rdsdata-admin",
+			},
+		},
+		{name: "not an arn", kind: arnKindRdsCluster, value: "my-cluster", expectedError: true},
+		{name: "unknown partition", kind: arnKindRdsCluster, value: "arn:aws-xx:rds:us-east-1:123456789012:cluster:my-cluster", expectedError: true},
+		{name: "other service", kind: arnKindRdsCluster, value: "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret", expectedError: true},
+		{name: "missing region", kind: arnKindRdsCluster, value: "arn:aws:rds::123456789012:cluster:my-cluster", expectedError: true},
+		{name: "invalid region", kind: arnKindRdsCluster, value: "arn:aws:rds:useast1:123456789012:cluster:my-cluster", expectedError: true},
+		{name: "region of another partition", kind: arnKindRdsCluster, value: "arn:aws:rds:cn-north-1:123456789012:cluster:my-cluster", expectedError: true},
+		{name: "region outside the partition", kind: arnKindRdsCluster, value: "arn:aws-cn:rds:us-east-1:123456789012:cluster:my-cluster", expectedError: true},
+		{name: "global resource with a region", kind: arnKindIamRole, value: "arn:aws:iam:us-east-1:123456789012:role/admin", expectedError: true},
+		{name: "invalid account", kind: arnKindRdsCluster, value: "arn:aws:rds:us-east-1:1234:cluster:my-cluster", expectedError: true},
+		{name: "other resource type", kind: arnKindRdsCluster, value: "arn:aws:rds:us-east-1:123456789012:db:my-instance", expectedError: true},
+		{name: "invalid cluster identifier", kind: arnKindRdsCluster, value: "arn:aws:rds:us-east-1:123456789012:cluster:my--cluster", expectedError: true},
+		{name: "iam user", kind: arnKindIamRole, value: "arn:aws:iam::123456789012:user/admin", expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := parseResourceArn(test.kind, test.value)
+
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("parseResourceArn(%q) = %+v, expected an error", test.value, parsed)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseResourceArn(%q) error: %s", test.value, err)
+			}
+
+			if parsed != test.expected {
+				t.Errorf("parseResourceArn(%q) = %+v, expected %+v", test.value, parsed, test.expected)
+			}
+		})
+	}
+}
+
+func TestArnRegion(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"arn:aws:rds:eu-west-3:123456789012:cluster:my-cluster", "eu-west-3"},
+		{"arn:aws:iam::123456789012:role/admin", ""},
+		{"my-cluster", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if region := arnRegion(test.value); region != test.expected {
+				t.Errorf("arnRegion(%q) = %q, expected %q", test.value, region, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The audit log formats, only JSON lines are supported for now.
+const auditLogFormatJsonl = "jsonl"
+
+// RdsDataProviderAuditLogModel describes the provider `audit_log` block data model.
+type RdsDataProviderAuditLogModel struct {
+	Path          types.String `tfsdk:"path"`
+	Format        types.String `tfsdk:"format"`
+	Workspace     types.String `tfsdk:"workspace"`
+	TagStatements types.Bool   `tfsdk:"tag_statements"`
+}
+
+// auditLogBlockSchema returns the provider `audit_log` block schema.
+func auditLogBlockSchema() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Records every statement the provider runs into a local file: timestamp, workspace, resource, " +
+			"cluster ARN, SQL (with credentials redacted), duration, number of records updated and the AWS request ID or error. " +
+			"Terraform does not share resource addresses with providers, so resources are identified by their type and " +
+			"MySQL account (e.g. `awsrdsdata_mysql_user['app'@'%']`)",
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The file the records are appended to (required), created if it does not exist",
+				Optional:            true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "The records format, only `jsonl` (one JSON object per line) is supported. Defaults to `jsonl`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(auditLogFormatJsonl),
+				},
+			},
+			"workspace": schema.StringAttribute{
+				MarkdownDescription: "The workspace recorded with every statement (e.g. `terraform.workspace`)",
+				Optional:            true,
+			},
+			"tag_statements": schema.BoolAttribute{
+				MarkdownDescription: "Whether to prefix every statement with a SQL comment carrying the workspace and resource " +
+					"(`/* terraform workspace=... resource=... */`), so database audit logs (e.g. Aurora Advanced Auditing) " +
+					"can be correlated with the audit log records. Defaults to `false`",
+				Optional: true,
+			},
+		},
+	}
+}
+
+// auditLog appends a record of every statement run by the provider to a file. The file is opened for every record,
+// the provider has no shutdown hook to close it.
+type auditLog struct {
+	mutex         sync.Mutex
+	path          string
+	workspace     string
+	tagStatements bool
+}
+
+// newAuditLog checks the `audit_log` block file can be written (model is nil when the block is not configured).
+func newAuditLog(model *RdsDataProviderAuditLogModel) (*auditLog, error) {
+	if model == nil {
+		return nil, nil
+	}
+
+	if model.Path.ValueString() == "" {
+		return nil, fmt.Errorf("the audit log `path` attribute is required")
+	}
+
+	file, err := os.OpenFile(model.Path.ValueString(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the audit log: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("unable to open the audit log: %w", err)
+	}
+
+	return &auditLog{
+		path:          model.Path.ValueString(),
+		workspace:     model.Workspace.ValueString(),
+		tagStatements: model.TagStatements.ValueBool(),
+	}, nil
+}
+
+// auditRecord is a JSON line of the audit log.
+type auditRecord struct {
+	Timestamp      string  `json:"timestamp"`
+	Workspace      string  `json:"workspace,omitempty"`
+	Resource       string  `json:"resource,omitempty"`
+	ClusterArn     string  `json:"cluster_arn"`
+	Sql            string  `json:"sql"`
+	DurationMs     float64 `json:"duration_ms"`
+	RecordsUpdated int64   `json:"records_updated"`
+	RequestId      string  `json:"request_id,omitempty"`
+	Error          string  `json:"error,omitempty"`
+}
+
+type auditResourceKey struct{}
+
+// withAuditResource returns a context identifying the resource running statements in the audit log,
+// e.g. `awsrdsdata_mysql_user['app'@'%']`.
+func withAuditResource(ctx context.Context, resourceType string, id string) context.Context {
+	return context.WithValue(ctx, auditResourceKey{}, fmt.Sprintf("%s[%s]", resourceType, id))
+}
+
+// auditResource returns the resource running statements, empty when unknown.
+func auditResource(ctx context.Context) string {
+	resource, _ := ctx.Value(auditResourceKey{}).(string)
+
+	return resource
+}
+
+// tag returns the statement prefixed with a SQL comment carrying the workspace and resource, when enabled.
+func (l *auditLog) tag(ctx context.Context, sql string) string {
+	if l == nil || !l.tagStatements {
+		return sql
+	}
+
+	tags := []string{"terraform"}
+
+	if l.workspace != "" {
+		tags = append(tags, "workspace="+l.workspace)
+	}
+
+	if resource := auditResource(ctx); resource != "" {
+		tags = append(tags, "resource="+resource)
+	}
+
+	// the comment must not end early
+	comment := strings.ReplaceAll(strings.Join(tags, " "), "*/", "* /")
+
+	return fmt.Sprintf("/* %s */ %s", comment, sql)
+}
+
+// record appends the statement run on the connection cluster (as sent, before tagging) to the audit log.
+// Write errors are logged but do not fail the statement, which already ran.
+func (l *auditLog) record(ctx context.Context, connection databaseConnection, sql string, start time.Time, output *rdsdata.ExecuteStatementOutput, err error) {
+	if l == nil {
+		return
+	}
+
+	record := auditRecord{
+		Timestamp:  start.UTC().Format(time.RFC3339Nano),
+		Workspace:  l.workspace,
+		Resource:   auditResource(ctx),
+		ClusterArn: connection.ResourceArn,
+		Sql:        redactSQL(sql),
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if output != nil {
+		record.RecordsUpdated = output.NumberOfRecordsUpdated
+		record.RequestId, _ = awsmiddleware.GetRequestIDMetadata(output.ResultMetadata)
+	}
+
+	if err != nil {
+		record.Error = redactErrorMessage(err.Error())
+
+		var responseErr *awshttp.ResponseError
+		if errors.As(err, &responseErr) {
+			record.RequestId = responseErr.ServiceRequestID()
+		}
+	}
+
+	line, writeErr := json.Marshal(record)
+	if writeErr == nil {
+		writeErr = l.write(append(line, '\n'))
+	}
+
+	if writeErr != nil {
+		tflog.Warn(ctx, "unable to write the SQL audit log", map[string]interface{}{
+			"path":  l.path,
+			"error": writeErr.Error(),
+			"sql":   record.Sql,
+		})
+	}
+}
+
+// write appends the line to the audit log file, closing it right away so records are not lost when the provider
+// process exits.
+func (l *auditLog) write(line []byte) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ER_UNKNOWN_SYSTEM_VARIABLE: `@@aurora_version` is only defined by Aurora MySQL.
+const mysqlErrorUnknownSystemVariable = 1193
+
+// Matches the numeric prefix of the MySQL version (`8.0.32`, `5.7.12-log`).
+var mysqlVersionRegex = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+
+// mysqlVersion is a MySQL server version.
+type mysqlVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v mysqlVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// atLeast reports whether the version is the same or newer than the other version.
+func (v mysqlVersion) atLeast(other mysqlVersion) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+
+	return v.Patch >= other.Patch
+}
+
+// parseMysqlVersion parses the `VERSION()` of a MySQL server.
+func parseMysqlVersion(version string) (mysqlVersion, error) {
+	match := mysqlVersionRegex.FindStringSubmatch(version)
+	if match == nil {
+		return mysqlVersion{}, fmt.Errorf("unexpected MySQL server version %q", version)
+	}
+
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+
+	return mysqlVersion{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+// serverCapability is a MySQL feature the generated SQL statements (or resource attributes) depend on.
+type serverCapability struct {
+	// Name describes the feature in diagnostics
+	Name       string
+	MinVersion mysqlVersion
+}
+
+var (
+	// `CREATE ROLE`, `GRANT <role> TO <account>`
+	capabilityRoles = serverCapability{Name: "roles", MinVersion: mysqlVersion{8, 0, 0}}
+	// Dynamic privileges (`BACKUP_ADMIN`, `ROLE_ADMIN`, ...), defined by the server components at runtime
+	capabilityDynamicPrivileges = serverCapability{Name: "dynamic privileges", MinVersion: mysqlVersion{8, 0, 0}}
+	// `ALTER USER ... RETAIN CURRENT PASSWORD` and `DISCARD OLD PASSWORD`
+	capabilityDualPasswords = serverCapability{Name: "dual passwords (`RETAIN CURRENT PASSWORD`)", MinVersion: mysqlVersion{8, 0, 14}}
+	// Revoking privileges on a database from a global grant (the `partial_revokes` system variable)
+	capabilityPartialRevokes = serverCapability{Name: "partial revokes", MinVersion: mysqlVersion{8, 0, 16}}
+	// Account host values in CIDR notation (`10.0.0.0/16`)
+	capabilityCidrHosts = serverCapability{Name: "CIDR host values (`10.0.0.0/16`)", MinVersion: mysqlVersion{8, 0, 23}}
+	// `REVOKE IF EXISTS ... IGNORE UNKNOWN USER`
+	capabilityRevokeIfExists = serverCapability{Name: "`REVOKE IF EXISTS`", MinVersion: mysqlVersion{8, 0, 30}}
+)
+
+// The features the provider detects.
+var serverCapabilitySet = []serverCapability{
+	capabilityRoles, capabilityDynamicPrivileges, capabilityDualPasswords, capabilityPartialRevokes,
+	capabilityCidrHosts, capabilityRevokeIfExists,
+}
+
+// serverCapabilities is the version of the MySQL server of a cluster, which determines the features it supports.
+type serverCapabilities struct {
+	Version mysqlVersion
+	// AuroraVersion is the Aurora MySQL version (`3.04.0`), empty for other servers
+	AuroraVersion string
+}
+
+// supports reports whether the server supports the feature.
+func (c *serverCapabilities) supports(capability serverCapability) bool {
+	return c.Version.atLeast(capability.MinVersion)
+}
+
+// String describes the server version in diagnostics (`MySQL 8.0.28, Aurora 3.04.0`).
+func (c *serverCapabilities) String() string {
+	if c.AuroraVersion == "" {
+		return "MySQL " + c.Version.String()
+	}
+
+	return fmt.Sprintf("MySQL %s, Aurora %s", c.Version, c.AuroraVersion)
+}
+
+// capabilities detects the MySQL server version of the connection cluster. The version is cached for the provider
+// lifetime once detected, failed detections are retried.
+func (d *RdsDataProviderData) capabilities(ctx context.Context, connection databaseConnection) (*serverCapabilities, error) {
+	if capabilities := d.cachedCapabilities(connection); capabilities != nil {
+		return capabilities, nil
+	}
+
+	capabilities := &serverCapabilities{}
+
+	if err := capabilities.detect(ctx, d, connection); err != nil {
+		return nil, err
+	}
+
+	value, _ := d.serverCapabilities.LoadOrStore(connection.ResourceArn, capabilities)
+
+	return value.(*serverCapabilities), nil
+}
+
+// cachedCapabilities returns the MySQL server version of the connection cluster if it was already detected, nil
+// otherwise.
+func (d *RdsDataProviderData) cachedCapabilities(connection databaseConnection) *serverCapabilities {
+	if value, ok := d.serverCapabilities.Load(connection.ResourceArn); ok {
+		return value.(*serverCapabilities)
+	}
+
+	return nil
+}
+
+func (c *serverCapabilities) detect(ctx context.Context, d *RdsDataProviderData, connection databaseConnection) error {
+	versionSqlQuery := "SELECT VERSION(), @@aurora_version"
+
+	versionResult, err := d.QueryStatement(ctx, connection, &rdsdata.ExecuteStatementInput{
+		Sql: &versionSqlQuery,
+	})
+
+	// Not an Aurora cluster
+	if isMysqlError(err, mysqlErrorUnknownSystemVariable) {
+		versionSqlQuery = "SELECT VERSION()"
+
+		versionResult, err = d.QueryStatement(ctx, connection, &rdsdata.ExecuteStatementInput{
+			Sql: &versionSqlQuery,
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(versionResult.Records) != 1 || len(versionResult.Records[0]) == 0 {
+		return fmt.Errorf("unexpected `%s` result: check response returned from the AWS rdsdata service API call", versionSqlQuery)
+	}
+
+	record := versionResult.Records[0]
+
+	if c.Version, err = parseMysqlVersion(stringField(record[0])); err != nil {
+		return err
+	}
+
+	if len(record) > 1 {
+		c.AuroraVersion = stringField(record[1])
+	}
+
+	var supported []string
+
+	for _, capability := range serverCapabilitySet {
+		if c.supports(capability) {
+			supported = append(supported, capability.Name)
+		}
+	}
+
+	tflog.Debug(ctx, "detected the MySQL server version", map[string]interface{}{
+		"cluster_arn":    connection.ResourceArn,
+		"version":        c.Version.String(),
+		"aurora_version": c.AuroraVersion,
+		"capabilities":   supported,
+	})
+
+	return nil
+}
+
+// detectedCapabilities returns the MySQL server version of the connection cluster, nil while the connection is not
+// known or when the version can't be detected. The version is detected once per cluster, including when planning
+// resources using version dependent attributes.
+func (d *RdsDataProviderData) detectedCapabilities(ctx context.Context, connection databaseConnection) *serverCapabilities {
+	if connection.ResourceArn == "" || connection.SecretArn == "" {
+		return nil
+	}
+
+	capabilities, err := d.capabilities(ctx, connection)
+	if err != nil {
+		tflog.Warn(ctx, "unable to detect the MySQL server version", map[string]interface{}{
+			"cluster_arn": connection.ResourceArn,
+			"error":       err.Error(),
+		})
+
+		return nil
+	}
+
+	return capabilities
+}
+
+// supports reports whether the MySQL server of the connection cluster supports the feature. The server is assumed
+// not to support it when its version can't be detected: the statements generated then work with every version.
+func (d *RdsDataProviderData) supports(ctx context.Context, connection databaseConnection, capability serverCapability) bool {
+	capabilities := d.detectedCapabilities(ctx, connection)
+
+	return capabilities != nil && capabilities.supports(capability)
+}
+
+// requireCapability returns an error diagnostic on the attribute when the MySQL server of the connection cluster
+// does not support the feature the attribute value needs. Nothing is checked when the server version can't be
+// detected: the statement itself fails on apply.
+func (d *RdsDataProviderData) requireCapability(ctx context.Context, connection databaseConnection, capability serverCapability, attributePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	capabilities := d.detectedCapabilities(ctx, connection)
+
+	if capabilities != nil && !capabilities.supports(capability) {
+		diags.AddAttributeError(
+			attributePath,
+			"Unsupported by the MySQL server version",
+			fmt.Sprintf(
+				"The %s cluster runs %s, but %s require MySQL >= %s",
+				connection.ResourceArn, capabilities, capability.Name, capability.MinVersion,
+			),
+		)
+	}
+
+	return diags
+}
+
+// requireHostCapabilities returns the error diagnostics of the host values the MySQL server of the connection cluster
+// does not support.
+func (d *RdsDataProviderData) requireHostCapabilities(ctx context.Context, connection databaseConnection, hosts []string, attributePath path.Path) diag.Diagnostics {
+	for _, host := range hosts {
+		if pattern, err := parseHostPattern(host); err == nil && pattern.Kind == hostPatternIPv4Cidr {
+			return d.requireCapability(ctx, connection, capabilityCidrHosts, attributePath)
+		}
+	}
+
+	return nil
+}
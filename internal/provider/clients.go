@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// The session name of the IAM roles assumed by the provider (visible in CloudTrail).
+const assumeRoleSessionName = "terraform-provider-awsrdsdata"
+
+// awsClientsKey identifies the AWS service clients built for a region and IAM role.
+type awsClientsKey struct {
+	Region string
+	// RoleArn is the assumed IAM role, empty for the provider credentials
+	RoleArn string
+}
+
+// awsClients holds the AWS service clients of a region and IAM role.
+type awsClients struct {
+	Rds            *rds.Client
+	RdsData        *rdsdata.Client
+	SecretsManager *secretsmanager.Client
+}
+
+// awsClientsCache lazily builds the AWS service clients of each region and IAM role, and reuses them across resources.
+// The credentials of an assumed role are cached (and refreshed before they expire) once for all regions.
+type awsClientsCache struct {
+	config      aws.Config
+	mutex       sync.Mutex
+	clients     map[awsClientsKey]*awsClients
+	credentials map[string]aws.CredentialsProvider
+}
+
+func newAwsClientsCache(config aws.Config) *awsClientsCache {
+	return &awsClientsCache{
+		config:      config,
+		clients:     map[awsClientsKey]*awsClients{},
+		credentials: map[string]aws.CredentialsProvider{},
+	}
+}
+
+// get returns the AWS service clients of the key region and IAM role, building them on first use.
+func (c *awsClientsCache) get(key awsClientsKey) *awsClients {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if clients, ok := c.clients[key]; ok {
+		return clients
+	}
+
+	config := c.config.Copy()
+	if key.Region != "" {
+		config.Region = key.Region
+	}
+
+	if key.RoleArn != "" {
+		credentials, ok := c.credentials[key.RoleArn]
+		if !ok {
+			credentials = aws.NewCredentialsCache(
+				stscreds.NewAssumeRoleProvider(sts.NewFromConfig(config), key.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+					o.RoleSessionName = assumeRoleSessionName
+				}),
+			)
+			c.credentials[key.RoleArn] = credentials
+		}
+
+		config.Credentials = credentials
+	}
+
+	clients := &awsClients{
+		Rds:            rds.NewFromConfig(config),
+		RdsData:        rdsdata.NewFromConfig(config),
+		SecretsManager: secretsmanager.NewFromConfig(config),
+	}
+
+	c.clients[key] = clients
+
+	return clients
+}
+
+// clientsFor returns the AWS service clients of the region embedded in resourceArn (falling back to the provider
+// default region when the ARN has none), using the connection IAM role. The clients are only returned when the
+// provider credentials, the connection and resourceArn belong to allowed AWS accounts.
+func (d *RdsDataProviderData) clientsFor(ctx context.Context, connection databaseConnection, resourceArn string) (*awsClients, error) {
+	region := arnRegion(resourceArn)
+	if region == "" {
+		region = d.DefaultRegion
+	}
+
+	if err := d.checkCallerAccount(ctx, region); err != nil {
+		return nil, err
+	}
+
+	if err := d.checkConnectionAccounts(connection); err != nil {
+		return nil, err
+	}
+
+	if err := d.checkArnAccount(resourceArn); err != nil {
+		return nil, err
+	}
+
+	return d.clients.get(awsClientsKey{Region: region, RoleArn: connection.AssumeRoleArn}), nil
+}
+
+// RdsClient returns the RDS client of the connection cluster.
+func (d *RdsDataProviderData) RdsClient(ctx context.Context, connection databaseConnection) (*rds.Client, error) {
+	clients, err := d.clientsFor(ctx, connection, connection.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.Rds, nil
+}
+
+// RdsDataClient returns the RDS Data client of the connection cluster.
+func (d *RdsDataProviderData) RdsDataClient(ctx context.Context, connection databaseConnection) (*rdsdata.Client, error) {
+	clients, err := d.clientsFor(ctx, connection, connection.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.RdsData, nil
+}
+
+// SecretsManagerClient returns the Secrets Manager client of the secretArn region, using the connection IAM role.
+func (d *RdsDataProviderData) SecretsManagerClient(ctx context.Context, connection databaseConnection, secretArn string) (*secretsmanager.Client, error) {
+	clients, err := d.clientsFor(ctx, connection, secretArn)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.SecretsManager, nil
+}
+
+// checkWritable ensures the provider can change the databases, it fails when the provider is read-only.
+func (d *RdsDataProviderData) checkWritable() error {
+	if d.ReadOnly {
+		return fmt.Errorf("the provider is read-only (`read_only = true`) and cannot change databases")
+	}
+
+	return nil
+}
+
+// ExecuteStatement runs a SQL statement against the connection cluster, with the connection credentials.
+// The statement is only sent when the connection AWS accounts are allowed, and writes are rejected when the
+// provider is read-only. Statements sent are recorded into the audit log, if any.
+func (d *RdsDataProviderData) ExecuteStatement(ctx context.Context, connection databaseConnection, input *rdsdata.ExecuteStatementInput) (*rdsdata.ExecuteStatementOutput, error) {
+	return d.executeStatement(ctx, connection, input, false)
+}
+
+// QueryStatement runs a SQL statement reading data (e.g. from a Read method), which must not change the database:
+// writes are always rejected.
+func (d *RdsDataProviderData) QueryStatement(ctx context.Context, connection databaseConnection, input *rdsdata.ExecuteStatementInput) (*rdsdata.ExecuteStatementOutput, error) {
+	return d.executeStatement(ctx, connection, input, true)
+}
+
+func (d *RdsDataProviderData) executeStatement(ctx context.Context, connection databaseConnection, input *rdsdata.ExecuteStatementInput, readOnly bool) (*rdsdata.ExecuteStatementOutput, error) {
+	if kind := classifyStatement(aws.ToString(input.Sql)); kind != statementKindRead {
+		if readOnly {
+			return nil, fmt.Errorf("refusing to run a %s statement while reading data: %s", kind, redactSQL(aws.ToString(input.Sql)))
+		}
+
+		if err := d.checkWritable(); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := d.RdsDataClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	input.ResourceArn = aws.String(connection.ResourceArn)
+	input.SecretArn = aws.String(connection.SecretArn)
+
+	sql := aws.ToString(input.Sql)
+	input.Sql = aws.String(d.AuditLog.tag(ctx, sql))
+
+	start := time.Now()
+	output, err := client.ExecuteStatement(ctx, input)
+	d.AuditLog.record(ctx, connection, sql, start, output, err)
+
+	if err != nil {
+		return output, newStatementError(sql, err)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+)
+
+// databaseConnection identifies the database cluster and credentials SQL statements run against,
+// and the IAM role (if any) assumed to call the AWS APIs.
+type databaseConnection struct {
+	ResourceArn   string
+	SecretArn     string
+	AssumeRoleArn string
+}
+
+// DatabaseConnectionModel describes the `database_connection` block data model.
+type DatabaseConnectionModel struct {
+	ResourceArn   ArnValue `tfsdk:"resource_arn"`
+	SecretArn     ArnValue `tfsdk:"secret_arn"`
+	AssumeRoleArn ArnValue `tfsdk:"assume_role_arn"`
+}
+
+// newDatabaseConnection resolves the connection of a resource, either from its `database_connection` block
+// or from its `database_resource_arn` and `database_secret_arn` attributes.
+func newDatabaseConnection(resourceArn ArnValue, secretArn ArnValue, connection *DatabaseConnectionModel) databaseConnection {
+	if connection != nil {
+		return databaseConnection{
+			ResourceArn:   connection.ResourceArn.ValueString(),
+			SecretArn:     connection.SecretArn.ValueString(),
+			AssumeRoleArn: connection.AssumeRoleArn.ValueString(),
+		}
+	}
+
+	return databaseConnection{
+		ResourceArn: resourceArn.ValueString(),
+		SecretArn:   secretArn.ValueString(),
+	}
+}
+
+// connectionBlockSchema returns the `database_connection` block schema shared by all resources.
+func connectionBlockSchema() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "The database cluster to run SQL queries against, and the IAM role to assume for it " +
+			"(e.g. to manage clusters of several AWS accounts with a single provider configuration). " +
+			"Conflicts with `database_resource_arn` and `database_secret_arn`",
+		Attributes: map[string]schema.Attribute{
+			"resource_arn": schema.StringAttribute{
+				MarkdownDescription: "The RDS database cluster ARN to run SQL queries against",
+				Required:            true,
+				CustomType:          ArnType{Kind: arnKindRdsCluster},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfConnectionChanged(),
+				},
+			},
+			"secret_arn": schema.StringAttribute{
+				MarkdownDescription: "The RDS database secret ARN to use for authentication",
+				Required:            true,
+				CustomType:          ArnType{Kind: arnKindSecretsManagerSecret},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfConnectionChanged(),
+				},
+			},
+			"assume_role_arn": schema.StringAttribute{
+				MarkdownDescription: "The ARN of an IAM role to assume for all the AWS API calls of the resource " +
+					"(RDS Data, RDS and Secrets Manager). The role credentials are cached and shared by the resources using the same role",
+				Optional:   true,
+				CustomType: ArnType{Kind: arnKindIamRole},
+			},
+		},
+	}
+}
+
+// connectionConfigValidators ensures the connection is configured either by the `database_resource_arn` and
+// `database_secret_arn` attributes or by the `database_connection` block.
+func connectionConfigValidators() []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("database_resource_arn"),
+			path.MatchRoot("database_connection"),
+		),
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("database_resource_arn"),
+			path.MatchRoot("database_secret_arn"),
+		),
+	}
+}
+
+// attributeGetter is implemented by tfsdk.Config, tfsdk.Plan and tfsdk.State.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// connectionFromAttributes resolves the connection of a resource from its plan or state.
+func connectionFromAttributes(ctx context.Context, data attributeGetter) (databaseConnection, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var resourceArn, secretArn ArnValue
+	var connection *DatabaseConnectionModel
+
+	diags.Append(data.GetAttribute(ctx, path.Root("database_resource_arn"), &resourceArn)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("database_secret_arn"), &secretArn)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("database_connection"), &connection)...)
+
+	return newDatabaseConnection(resourceArn, secretArn, connection), diags
+}
+
+// requiresReplaceIfConnectionChanged replaces the resource when it moves to another database cluster or
+// credentials secret, but not when the same cluster moves between `database_resource_arn` and the `database_connection` block.
+func requiresReplaceIfConnectionChanged() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			planConnection, diags := connectionFromAttributes(ctx, req.Plan)
+			resp.Diagnostics.Append(diags...)
+
+			stateConnection, diags := connectionFromAttributes(ctx, req.State)
+			resp.Diagnostics.Append(diags...)
+
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.RequiresReplace = planConnection.ResourceArn != stateConnection.ResourceArn ||
+				planConnection.SecretArn != stateConnection.SecretArn
+		},
+		"Changing the database cluster or secret requires replacement.",
+		"Changing the database cluster or secret requires replacement.",
+	)
+}
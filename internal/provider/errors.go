@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// The MySQL server error numbers the provider handles.
+const (
+	// ER_DBACCESS_DENIED_ERROR: the user lacks privileges on the database
+	mysqlErrorDatabaseAccessDenied = 1044
+	// ER_ACCESS_DENIED_ERROR: the credentials are rejected
+	mysqlErrorAccessDenied = 1045
+	// ER_NONEXISTING_GRANT: the account holds no privilege on the object
+	mysqlErrorNonexistingGrant = 1141
+	// ER_SPECIFIC_ACCESS_DENIED_ERROR: the statement needs a privilege the user lacks (e.g. SUPER)
+	mysqlErrorSpecificAccessDenied = 1227
+	// ER_CANNOT_USER: CREATE, ALTER, RENAME or DROP USER failed (the account exists or not)
+	mysqlErrorCannotUser = 1396
+)
+
+// Matches the MySQL error number of RDS Data API database errors (`Database error code: 1141. Message: ...`).
+var mysqlErrorCodeRegex = regexp.MustCompile(`Database error code: (\d+)`)
+
+// statementErrorClass describes a class of statement failures and how to fix them.
+type statementErrorClass struct {
+	Summary string
+	Hint    string
+}
+
+var (
+	statementErrorAccessDenied = statementErrorClass{
+		Summary: "Access denied to the RDS Data API",
+		Hint: "The provider AWS credentials (or assumed role) need the `rds-data:ExecuteStatement` permission on the " +
+			"cluster, and `secretsmanager:GetSecretValue` on the database secret",
+	}
+	statementErrorHttpEndpoint = statementErrorClass{
+		Summary: "HTTP endpoint not enabled on cluster",
+		Hint: "The RDS Data API must be enabled on the cluster (e.g. `enable_http_endpoint = true` on the " +
+			"`aws_rds_cluster` resource)",
+	}
+	statementErrorSecret = statementErrorClass{
+		Summary: "Secret lacks permissions",
+		Hint: "The database secret could not be used: make sure it exists, holds the `username` and `password` keys, " +
+			"and that the provider AWS credentials can read it (`secretsmanager:GetSecretValue`) and decrypt it with its KMS key",
+	}
+	statementErrorTimeout = statementErrorClass{
+		Summary: "Statement timed out",
+		Hint: "The RDS Data API gave up waiting for the statement: the cluster may be overloaded or the statement " +
+			"waiting on a lock held by another session. Retry once the cluster is idle",
+	}
+	statementErrorUnavailable = statementErrorClass{
+		Summary: "Database unavailable",
+		Hint: "The cluster did not accept the connection: it may be paused (Aurora Serverless), rebooting or failing " +
+			"over. Retry once it is available",
+	}
+)
+
+// The classes of the MySQL error numbers.
+var mysqlErrorClasses = map[int]statementErrorClass{
+	mysqlErrorDatabaseAccessDenied: {
+		Summary: "Database access denied",
+		Hint: "The database user of the connection secret lacks privileges on the database: it can only grant the " +
+			"privileges it holds itself `WITH GRANT OPTION`",
+	},
+	mysqlErrorAccessDenied: {
+		Summary: "Database authentication failed",
+		Hint: "The database rejected the credentials of the connection secret: make sure its `username` and `password` " +
+			"match the database user (e.g. after a password rotation)",
+	},
+	mysqlErrorNonexistingGrant: {
+		Summary: "Grant not defined",
+		Hint:    "The account holds no privilege on the database, they may have been revoked outside Terraform",
+	},
+	mysqlErrorSpecificAccessDenied: {
+		Summary: "Privilege not allowed on RDS",
+		Hint: "The statement needs a privilege the database user of the connection secret does not hold. RDS never " +
+			"grants some privileges (e.g. `SUPER`, `FILE`, `SHUTDOWN`), even to the master user: remove them from the configuration",
+	},
+	mysqlErrorCannotUser: {
+		Summary: "MySQL account operation failed",
+		Hint: "The account already exists (creating or renaming it) or does not exist (altering or dropping it): " +
+			"import it, or refresh the state if it was changed outside Terraform",
+	},
+}
+
+// statementError is a failed RDS Data API statement, classified to render actionable diagnostics.
+type statementError struct {
+	// Class is empty for unclassified errors
+	Class statementErrorClass
+	// MysqlCode is the MySQL error number, 0 when the statement did not reach the database
+	MysqlCode int
+	// Sql is the redacted statement
+	Sql string
+	Err error
+}
+
+func (e *statementError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *statementError) Unwrap() error {
+	return e.Err
+}
+
+// newStatementError classifies the error returned by the RDS Data API for the SQL statement.
+func newStatementError(sql string, err error) *statementError {
+	statementErr := &statementError{Sql: redactSQL(sql), Err: err}
+
+	var (
+		badRequest          *rdsdatatypes.BadRequestException
+		databaseError       *rdsdatatypes.DatabaseErrorException
+		forbidden           *rdsdatatypes.ForbiddenException
+		accessDenied        *rdsdatatypes.AccessDeniedException
+		httpEndpoint        *rdsdatatypes.HttpEndpointNotEnabledException
+		secretsError        *rdsdatatypes.SecretsErrorException
+		invalidSecret       *rdsdatatypes.InvalidSecretException
+		statementTimeout    *rdsdatatypes.StatementTimeoutException
+		databaseUnavailable *rdsdatatypes.DatabaseUnavailableException
+	)
+
+	message := ""
+
+	switch {
+	case errors.As(err, &forbidden), errors.As(err, &accessDenied):
+		statementErr.Class = statementErrorAccessDenied
+	case errors.As(err, &httpEndpoint):
+		statementErr.Class = statementErrorHttpEndpoint
+	case errors.As(err, &secretsError), errors.As(err, &invalidSecret):
+		statementErr.Class = statementErrorSecret
+	case errors.As(err, &statementTimeout):
+		statementErr.Class = statementErrorTimeout
+	case errors.As(err, &databaseUnavailable):
+		statementErr.Class = statementErrorUnavailable
+	case errors.As(err, &badRequest):
+		message = aws.ToString(badRequest.Message)
+	case errors.As(err, &databaseError):
+		message = aws.ToString(databaseError.Message)
+	}
+
+	if message == "" {
+		return statementErr
+	}
+
+	// Bad requests carry database errors, and errors raised before reaching the database
+	if match := mysqlErrorCodeRegex.FindStringSubmatch(message); match != nil {
+		statementErr.MysqlCode, _ = strconv.Atoi(match[1])
+
+		if class, ok := mysqlErrorClasses[statementErr.MysqlCode]; ok {
+			statementErr.Class = class
+		} else {
+			statementErr.Class = statementErrorClass{Summary: fmt.Sprintf("MySQL error %d", statementErr.MysqlCode)}
+		}
+
+		return statementErr
+	}
+
+	switch message = strings.ToLower(message); {
+	case strings.Contains(message, "httpendpoint"), strings.Contains(message, "http endpoint"):
+		statementErr.Class = statementErrorHttpEndpoint
+	case strings.Contains(message, "secret"):
+		statementErr.Class = statementErrorSecret
+	}
+
+	return statementErr
+}
+
+// isMysqlError reports whether the statement failed with the MySQL error number.
+func isMysqlError(err error, code int) bool {
+	var statementErr *statementError
+
+	return errors.As(err, &statementErr) && statementErr.MysqlCode == code
+}
+
+// addOperationError adds the error diagnostic of a failed resource operation. Statement errors are rendered with
+// their class, remediation hint and redacted statement.
+func addOperationError(diags *diag.Diagnostics, summary string, err error) {
+	var statementErr *statementError
+
+	if !errors.As(err, &statementErr) {
+		diags.AddError(summary, err.Error())
+		return
+	}
+
+	// MySQL errors may quote the statement
+	detail := redactErrorMessage(err.Error())
+
+	if statementErr.Class.Summary != "" {
+		summary += ": " + statementErr.Class.Summary
+	}
+
+	if statementErr.Class.Hint != "" {
+		detail += "\n\n" + statementErr.Class.Hint
+	}
+
+	diags.AddError(summary, detail+"\n\nStatement: "+statementErr.Sql)
+}
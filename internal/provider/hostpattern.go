@@ -0,0 +1,321 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the host pattern types fully satisfy framework interfaces.
+var (
+	_ basetypes.StringTypable                    = HostPatternType{}
+	_ basetypes.StringValuableWithSemanticEquals = HostPatternValue{}
+	_ xattr.ValidateableAttribute                = HostPatternValue{}
+	_ validator.String                           = hostPatternValidator{}
+)
+
+// hostPatternKind identifies the form of a MySQL account host value.
+type hostPatternKind int
+
+const (
+	// `%`, any host
+	hostPatternAny hostPatternKind = iota
+	// `db.example.com`, `localhost`
+	hostPatternHostname
+	// `%.example.com`, `app-_.local`
+	hostPatternHostnameWildcard
+	// `10.0.0.1`
+	hostPatternIPv4
+	// `10.0.%`, `192.168.1._`
+	hostPatternIPv4Wildcard
+	// `192.168.1.0/255.255.255.0`
+	hostPatternIPv4Netmask
+	// `10.0.0.0/16` (MySQL >= 8.0.23)
+	hostPatternIPv4Cidr
+	// `::1`, `fe80::1`
+	hostPatternIPv6
+	// `fe80::%`
+	hostPatternIPv6Wildcard
+)
+
+// The host value forms accepted by MySQL, used in the validation diagnostics.
+const hostPatternForms = "`%` (any host), a hostname (`db.example.com`), a hostname pattern (`%.example.com`), " +
+	"an IPv4 address (`10.0.0.1`), an IPv4 pattern (`10.0.%`), an IPv4 address with a netmask (`10.0.0.0/255.255.0.0`), " +
+	"an IPv4 CIDR block (`10.0.0.0/16`, MySQL >= 8.0.23), an IPv6 address (`fe80::1`) or an IPv6 pattern (`fe80::%`)"
+
+var (
+	hostnamePatternRegex  = regexp.MustCompile(`^([a-zA-Z0-9%_]([a-zA-Z0-9%_\-]*[a-zA-Z0-9%_])?)(\.([a-zA-Z0-9%_]([a-zA-Z0-9%_\-]*[a-zA-Z0-9%_])?))*$`)
+	ipv4PatternRegex      = regexp.MustCompile(`^[0-9%_]+(\.[0-9%_]+){0,3}$`)
+	ipv6WildcardRegex     = regexp.MustCompile(`^[0-9a-fA-F:]*:[0-9a-fA-F:]*[%_]$`)
+	ipv4OctetPatternRegex = regexp.MustCompile(`^[0-9]{1,3}$`)
+)
+
+// hostPattern is a parsed MySQL account host value.
+type hostPattern struct {
+	Kind hostPatternKind
+	// Canonical is the host value as stored by MySQL (lowercase hostnames, compressed IPv6 addresses)
+	Canonical string
+}
+
+// IsWildcard reports whether the host value matches more than a single host.
+func (h hostPattern) IsWildcard() bool {
+	switch h.Kind {
+	case hostPatternAny, hostPatternHostnameWildcard, hostPatternIPv4Wildcard,
+		hostPatternIPv4Netmask, hostPatternIPv4Cidr, hostPatternIPv6Wildcard:
+		return true
+	}
+
+	return false
+}
+
+// parseHostPattern parses and canonicalizes a MySQL account host value.
+func parseHostPattern(host string) (hostPattern, error) {
+	switch {
+	case host == "%":
+		return hostPattern{Kind: hostPatternAny, Canonical: host}, nil
+	case host == "":
+		return hostPattern{}, fmt.Errorf("the host value cannot be empty, use `%%` to match any host")
+	case len(host) > 255:
+		return hostPattern{}, fmt.Errorf("the host value cannot be longer than 255 characters")
+	case strings.Contains(host, "/"):
+		return parseIPv4Mask(host)
+	case strings.Contains(host, ":"):
+		return parseIPv6Pattern(host)
+	case ipv4PatternRegex.MatchString(host):
+		return parseIPv4Pattern(host)
+	case hostnamePatternRegex.MatchString(host):
+		kind := hostPatternHostname
+		if strings.ContainsAny(host, "%_") {
+			kind = hostPatternHostnameWildcard
+		}
+
+		return hostPattern{Kind: kind, Canonical: strings.ToLower(host)}, nil
+	}
+
+	return hostPattern{}, fmt.Errorf("%q is not a valid MySQL host value, expected %s", host, hostPatternForms)
+}
+
+func parseIPv4Pattern(host string) (hostPattern, error) {
+	if !strings.ContainsAny(host, "%_") {
+		address, err := netip.ParseAddr(host)
+		if err != nil || !address.Is4() {
+			return hostPattern{}, fmt.Errorf("%q looks like an IPv4 address, but it is not valid (e.g. `10.0.0.1`)", host)
+		}
+
+		return hostPattern{Kind: hostPatternIPv4, Canonical: address.String()}, nil
+	}
+
+	for _, octet := range strings.Split(host, ".") {
+		if !ipv4OctetPatternRegex.MatchString(octet) {
+			continue
+		}
+
+		if value, _ := strconv.Atoi(octet); value > 255 {
+			return hostPattern{}, fmt.Errorf("%q looks like an IPv4 pattern, but %s is not a valid octet (e.g. `10.0.%%`)", host, octet)
+		}
+	}
+
+	return hostPattern{Kind: hostPatternIPv4Wildcard, Canonical: host}, nil
+}
+
+func parseIPv4Mask(host string) (hostPattern, error) {
+	addressPart, maskPart, _ := strings.Cut(host, "/")
+
+	address, err := netip.ParseAddr(addressPart)
+	if err != nil || !address.Is4() {
+		return hostPattern{}, fmt.Errorf(
+			"%q looks like an IPv4 address with a netmask or CIDR prefix, but %q is not a valid IPv4 address "+
+				"(e.g. `10.0.0.0/255.255.0.0` or `10.0.0.0/16`)", host, addressPart,
+		)
+	}
+
+	// CIDR prefix length (MySQL >= 8.0.23)
+	if prefixLength, err := strconv.Atoi(maskPart); err == nil {
+		if prefixLength < 0 || prefixLength > 32 {
+			return hostPattern{}, fmt.Errorf("%q looks like an IPv4 CIDR block, but the prefix length must be between 0 and 32", host)
+		}
+
+		prefix := netip.PrefixFrom(address, prefixLength)
+		if prefix.Masked().Addr() != address {
+			return hostPattern{}, fmt.Errorf(
+				"%q looks like an IPv4 CIDR block, but the address has host bits set (did you mean %q?)", host, prefix.Masked().String(),
+			)
+		}
+
+		return hostPattern{Kind: hostPatternIPv4Cidr, Canonical: prefix.String()}, nil
+	}
+
+	mask, err := netip.ParseAddr(maskPart)
+	if err != nil || !mask.Is4() {
+		return hostPattern{}, fmt.Errorf(
+			"%q looks like an IPv4 address with a netmask, but %q is neither a valid netmask nor a CIDR prefix length "+
+				"(e.g. `10.0.0.0/255.255.0.0` or `10.0.0.0/16`)", host, maskPart,
+		)
+	}
+
+	maskBytes := mask.As4()
+	maskBits := uint32(maskBytes[0])<<24 | uint32(maskBytes[1])<<16 | uint32(maskBytes[2])<<8 | uint32(maskBytes[3])
+	prefixLength := bits.LeadingZeros32(^maskBits)
+
+	if bits.OnesCount32(maskBits) != prefixLength {
+		return hostPattern{}, fmt.Errorf("%q looks like an IPv4 address with a netmask, but the %s netmask is not contiguous", host, maskPart)
+	}
+
+	if masked := netip.PrefixFrom(address, prefixLength).Masked().Addr(); masked != address {
+		return hostPattern{}, fmt.Errorf(
+			"%q looks like an IPv4 address with a netmask, but the address has host bits set (did you mean %q?)",
+			host, masked.String()+"/"+maskPart,
+		)
+	}
+
+	return hostPattern{Kind: hostPatternIPv4Netmask, Canonical: address.String() + "/" + mask.String()}, nil
+}
+
+func parseIPv6Pattern(host string) (hostPattern, error) {
+	if ipv6WildcardRegex.MatchString(host) {
+		return hostPattern{Kind: hostPatternIPv6Wildcard, Canonical: strings.ToLower(host)}, nil
+	}
+
+	address, err := netip.ParseAddr(host)
+	if err != nil || !address.Is6() || address.Zone() != "" {
+		return hostPattern{}, fmt.Errorf("%q looks like an IPv6 address, but it is not valid (e.g. `fe80::1` or `fe80::%%`)", host)
+	}
+
+	return hostPattern{Kind: hostPatternIPv6, Canonical: address.String()}, nil
+}
+
+// canonicalHostPattern returns the canonical form of the host value, or the value itself when it's not valid.
+func canonicalHostPattern(host string) string {
+	pattern, err := parseHostPattern(host)
+	if err != nil {
+		return host
+	}
+
+	return pattern.Canonical
+}
+
+// HostPatternType is the attribute type of MySQL account host values.
+type HostPatternType struct {
+	basetypes.StringType
+}
+
+func (t HostPatternType) String() string {
+	return "HostPatternType"
+}
+
+func (t HostPatternType) ValueType(ctx context.Context) attr.Value {
+	return HostPatternValue{}
+}
+
+func (t HostPatternType) Equal(o attr.Type) bool {
+	other, ok := o.(HostPatternType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t HostPatternType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return HostPatternValue{StringValue: in}, nil
+}
+
+func (t HostPatternType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+
+	return stringValuable, nil
+}
+
+// HostPatternValue is a MySQL account host value: host values with the same canonical form are semantically equal.
+type HostPatternValue struct {
+	basetypes.StringValue
+}
+
+// NewHostPatternValue returns a known host value.
+func NewHostPatternValue(host string) HostPatternValue {
+	return HostPatternValue{StringValue: basetypes.NewStringValue(host)}
+}
+
+func (v HostPatternValue) Type(ctx context.Context) attr.Type {
+	return HostPatternType{}
+}
+
+func (v HostPatternValue) Equal(o attr.Value) bool {
+	other, ok := o.(HostPatternValue)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v HostPatternValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(HostPatternValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+
+		return false, diags
+	}
+
+	return canonicalHostPattern(v.ValueString()) == canonicalHostPattern(newValue.ValueString()), diags
+}
+
+func (v HostPatternValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := parseHostPattern(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid MySQL Host Value", err.Error())
+	}
+}
+
+// hostPatternValidator validates MySQL account host values of plain string attributes (e.g. set elements).
+type hostPatternValidator struct{}
+
+func (v hostPatternValidator) Description(ctx context.Context) string {
+	return "value must be a valid MySQL host value"
+}
+
+func (v hostPatternValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v hostPatternValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := parseHostPattern(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid MySQL Host Value", err.Error())
+	}
+}
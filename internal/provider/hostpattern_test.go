@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseHostPattern(t *testing.T) {
+	tests := []struct {
+		host              string
+		expectedKind      hostPatternKind
+		expectedCanonical string
+		expectedError     bool
+	}{
+		{host: "%", expectedKind: hostPatternAny, expectedCanonical: "%"},
+		{host: "localhost", expectedKind: hostPatternHostname, expectedCanonical: "localhost"},
+		{host: "DB.Example.com", expectedKind: hostPatternHostname, expectedCanonical: "db.example.com"},
+		{host: "%.example.com", expectedKind: hostPatternHostnameWildcard, expectedCanonical: "%.example.com"},
+		{host: "app-_.local", expectedKind: hostPatternHostnameWildcard, expectedCanonical: "app-_.local"},
+		{host: "10.0.0.1", expectedKind: hostPatternIPv4, expectedCanonical: "10.0.0.1"},
+		{host: "10.0.%", expectedKind: hostPatternIPv4Wildcard, expectedCanonical: "10.0.%"},
+		{host: "192.168.1._", expectedKind: hostPatternIPv4Wildcard, expectedCanonical: "192.168.1._"},
+		{host: "192.168.1.0/255.255.255.0", expectedKind: hostPatternIPv4Netmask, expectedCanonical: "192.168.1.0/255.255.255.0"},
+		{host: "10.0.0.0/16", expectedKind: hostPatternIPv4Cidr, expectedCanonical: "10.0.0.0/16"},
+		{host: "0.0.0.0/0", expectedKind: hostPatternIPv4Cidr, expectedCanonical: "0.0.0.0/0"},
+		{host: "::1", expectedKind: hostPatternIPv6, expectedCanonical: "::1"},
+		{host: "FE80:0:0:0:0:0:0:1", expectedKind: hostPatternIPv6, expectedCanonical: "fe80::1"},
+		{host: "FE80::%", expectedKind: hostPatternIPv6Wildcard, expectedCanonical: "fe80::%"},
+		{host: "", expectedError: true},
+		{host: strings.Repeat("a", 256), expectedError: true},
+		{host: "10.0.0.256", expectedError: true},
+		{host: "10.0.300.%", expectedError: true},
+		{host: "10.0.0.1/16", expectedError: true},
+		{host: "10.0.0.0/33", expectedError: true},
+		{host: "10.0.0.0/255.0.255.0", expectedError: true},
+		{host: "10.0.0.1/255.255.255.0", expectedError: true},
+		{host: "example.com/24", expectedError: true},
+		{host: "fe80::1%eth0", expectedError: true},
+		{host: "not a host", expectedError: true},
+		{host: "-example.com", expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			pattern, err := parseHostPattern(test.host)
+
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("parseHostPattern(%q) = %+v, expected an error", test.host, pattern)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseHostPattern(%q) error: %s", test.host, err)
+			}
+
+			if pattern.Kind != test.expectedKind || pattern.Canonical != test.expectedCanonical {
+				t.Errorf(
+					"parseHostPattern(%q) = %+v, expected {Kind:%d Canonical:%s}",
+					test.host, pattern, test.expectedKind, test.expectedCanonical,
+				)
+			}
+		})
+	}
+}
+
+func TestHostPatternValueSemanticEquals(t *testing.T) {
+	tests := []struct {
+		host     string
+		other    string
+		expected bool
+	}{
+		{"DB.example.com", "db.example.com", true},
+		{"fe80:0:0:0:0:0:0:1", "fe80::1", true},
+		{"10.0.0.1", "10.0.0.1", true},
+		{"10.0.0.1", "10.0.0.2", false},
+		{"%", "%.example.com", false},
+		{"not a host", "Not A Host", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.host+" "+test.other, func(t *testing.T) {
+			equal, diags := NewHostPatternValue(test.host).StringSemanticEquals(context.Background(), NewHostPatternValue(test.other))
+			if diags.HasError() {
+				t.Fatalf("StringSemanticEquals() error: %v", diags)
+			}
+
+			if equal != test.expected {
+				t.Errorf("StringSemanticEquals(%q, %q) = %t, expected %t", test.host, test.other, equal, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+// The minimum length of the passwords set for users, whatever their source.
+const minPasswordLength = 16
+
+// Prefix of the password fingerprints stored into the Terraform state.
+const passwordFingerprintPrefix = "sha256"
+
+// passwordFingerprint returns a salted SHA-256 fingerprint of the password, formatted as
+// `sha256:<hex salt>:<hex digest>`, so password changes can be detected without storing the password.
+func passwordFingerprint(password string) (string, error) {
+	salt := make([]byte, 16)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("unable to generate the password fingerprint salt: %w", err)
+	}
+
+	return saltedPasswordFingerprint(password, salt), nil
+}
+
+// passwordMatchesFingerprint reports whether the password matches a fingerprint computed by passwordFingerprint.
+func passwordMatchesFingerprint(password string, fingerprint string) bool {
+	parts := strings.Split(fingerprint, ":")
+	if len(parts) != 3 || parts[0] != passwordFingerprintPrefix {
+		return false
+	}
+
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(saltedPasswordFingerprint(password, salt)), []byte(fingerprint)) == 1
+}
+
+func saltedPasswordFingerprint(password string, salt []byte) string {
+	digest := sha256.Sum256(append(append([]byte{}, salt...), password...))
+
+	return fmt.Sprintf("%s:%s:%s", passwordFingerprintPrefix, hex.EncodeToString(salt), hex.EncodeToString(digest[:]))
+}
+
+// Character classes the generated passwords are built from.
+const (
+	passwordLowercaseChars = "abcdefghijklmnopqrstuvwxyz"
+	passwordUppercaseChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordNumberChars    = "0123456789"
+	// quotes and backslashes are left out on purpose, so passwords are always safe to embed in SQL string literals
+	passwordSpecialChars = "!#%*+-.:=?@^_~"
+
+	defaultPasswordCharset = passwordLowercaseChars + passwordUppercaseChars + passwordNumberChars + passwordSpecialChars
+)
+
+// passwordPolicy describes the password requirements enforced by the MySQL `validate_password` component (or plugin).
+type passwordPolicy struct {
+	Length           int
+	MixedCaseCount   int
+	NumberCount      int
+	SpecialCharCount int
+}
+
+// fetchPasswordPolicy reads the password requirements enforced by the server, an empty policy is returned when
+// the `validate_password` component (or plugin) is not active.
+func fetchPasswordPolicy(ctx context.Context, providerData *RdsDataProviderData, connection databaseConnection) (passwordPolicy, error) {
+	policy := passwordPolicy{}
+
+	passwordVariablesSqlQuery := "SHOW VARIABLES LIKE 'validate_password%'"
+
+	passwordVariablesStatementOpts := rdsdata.ExecuteStatementInput{
+		Sql: &passwordVariablesSqlQuery,
+	}
+
+	passwordVariablesResult, err := providerData.QueryStatement(ctx, connection, &passwordVariablesStatementOpts)
+	if err != nil {
+		return policy, err
+	}
+
+	variables := map[string]string{}
+
+	for _, record := range passwordVariablesResult.Records {
+		if len(record) < 2 {
+			continue
+		}
+
+		name, nameOk := record[0].(*rdsdatatypes.FieldMemberStringValue)
+		value, valueOk := record[1].(*rdsdatatypes.FieldMemberStringValue)
+
+		if nameOk && valueOk {
+			// the component uses `validate_password.<name>` variables, the (5.7) plugin `validate_password_<name>` ones
+			variables[strings.Replace(name.Value, ".", "_", 1)] = value.Value
+		}
+	}
+
+	if len(variables) == 0 {
+		return policy, nil
+	}
+
+	policy.Length, _ = strconv.Atoi(variables["validate_password_length"])
+
+	// The LOW policy only checks the password length
+	switch strings.ToUpper(variables["validate_password_policy"]) {
+	case "0", "LOW":
+		return policy, nil
+	}
+
+	policy.MixedCaseCount, _ = strconv.Atoi(variables["validate_password_mixed_case_count"])
+	policy.NumberCount, _ = strconv.Atoi(variables["validate_password_number_count"])
+	policy.SpecialCharCount, _ = strconv.Atoi(variables["validate_password_special_char_count"])
+
+	return policy, nil
+}
+
+// generatePassword returns a random password of (at least) the given length, built from the charset characters
+// and satisfying the password policy requirements.
+func generatePassword(length int, charset string, policy passwordPolicy) (string, error) {
+	if charset == "" {
+		charset = defaultPasswordCharset
+	}
+
+	var lowercase, uppercase, numbers, specials []rune
+
+	for _, char := range charset {
+		switch {
+		case strings.ContainsRune(passwordLowercaseChars, char):
+			lowercase = append(lowercase, char)
+		case strings.ContainsRune(passwordUppercaseChars, char):
+			uppercase = append(uppercase, char)
+		case strings.ContainsRune(passwordNumberChars, char):
+			numbers = append(numbers, char)
+		default:
+			specials = append(specials, char)
+		}
+	}
+
+	requirements := []struct {
+		name  string
+		chars []rune
+		count int
+	}{
+		{"lowercase letters", lowercase, policy.MixedCaseCount},
+		{"uppercase letters", uppercase, policy.MixedCaseCount},
+		{"digits", numbers, policy.NumberCount},
+		{"special characters", specials, policy.SpecialCharCount},
+	}
+
+	password := []rune{}
+
+	for _, requirement := range requirements {
+		if requirement.count > 0 && len(requirement.chars) == 0 {
+			return "", fmt.Errorf(
+				"the password charset has no %s, but the server password policy requires at least %d",
+				requirement.name, requirement.count,
+			)
+		}
+
+		for i := 0; i < requirement.count; i++ {
+			char, err := randomRune(requirement.chars)
+			if err != nil {
+				return "", err
+			}
+
+			password = append(password, char)
+		}
+	}
+
+	if policy.Length > length {
+		length = policy.Length
+	}
+
+	allChars := []rune(charset)
+
+	for len(password) < length {
+		char, err := randomRune(allChars)
+		if err != nil {
+			return "", err
+		}
+
+		password = append(password, char)
+	}
+
+	// Shuffle, so the required characters are not always at the beginning of the password
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomRune(chars []rune) (rune, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, fmt.Errorf("unable to generate a random password: %w", err)
+	}
+
+	return chars[i.Int64()], nil
+}
+
+// MySQL `caching_sha2_password` authentication strings are formatted as `$A$<rounds / 1000>$<salt><digest>`.
+const (
+	cachingSha2PasswordPrefix     = "$A$"
+	cachingSha2PasswordSaltLength = 20
+	cachingSha2PasswordHashLength = 43
+)
+
+// authStringMatchesPassword reports whether the (hex encoded) `mysql.user.authentication_string` value was
+// computed from the password by the given authentication plugin. The second return value is false when
+// the authentication plugin is not supported, in which case the password cannot be checked.
+func authStringMatchesPassword(plugin string, hexAuthString string, password string) (bool, bool) {
+	authString, err := hex.DecodeString(hexAuthString)
+	if err != nil {
+		return false, false
+	}
+
+	switch plugin {
+	case mysqlAuthPluginNativePassword:
+		return subtle.ConstantTimeCompare(authString, []byte(mysqlNativePasswordHash(password))) == 1, true
+	case mysqlAuthPluginCachingSha2Password:
+		prefixLength := len(cachingSha2PasswordPrefix) + 4
+
+		if len(authString) != prefixLength+cachingSha2PasswordSaltLength+cachingSha2PasswordHashLength ||
+			!strings.HasPrefix(string(authString), cachingSha2PasswordPrefix) {
+			return false, true
+		}
+
+		rounds, err := strconv.ParseInt(string(authString[len(cachingSha2PasswordPrefix):prefixLength-1]), 16, 32)
+		if err != nil {
+			return false, true
+		}
+
+		salt := authString[prefixLength : prefixLength+cachingSha2PasswordSaltLength]
+		digest := sha256Crypt([]byte(password), salt, int(rounds)*1000)
+
+		return subtle.ConstantTimeCompare(authString[prefixLength+cachingSha2PasswordSaltLength:], []byte(digest)) == 1, true
+	}
+
+	return false, false
+}
+
+// mysqlNativePasswordHash returns the `mysql_native_password` authentication string of the password,
+// that is `*` followed by the uppercase hex encoded SHA1(SHA1(password)).
+func mysqlNativePasswordHash(password string) string {
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+
+	return "*" + strings.ToUpper(hex.EncodeToString(stage2[:]))
+}
+
+// sha256Crypt implements the SHA-256 based crypt algorithm (https://www.akkadia.org/drepper/SHA-crypt.txt)
+// used by the `caching_sha2_password` authentication plugin, returning the base64 like encoded digest only.
+func sha256Crypt(password []byte, salt []byte, rounds int) string {
+	digestB := sha256.New()
+	digestB.Write(password)
+	digestB.Write(salt)
+	digestB.Write(password)
+	sumB := digestB.Sum(nil)
+
+	digestA := sha256.New()
+	digestA.Write(password)
+	digestA.Write(salt)
+
+	for i := len(password); i > 0; i -= sha256.Size {
+		digestA.Write(sumB[:min(i, sha256.Size)])
+	}
+
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			digestA.Write(sumB)
+		} else {
+			digestA.Write(password)
+		}
+	}
+
+	sumA := digestA.Sum(nil)
+
+	digestDP := sha256.New()
+	for i := 0; i < len(password); i++ {
+		digestDP.Write(password)
+	}
+
+	sequenceP := repeatBytes(digestDP.Sum(nil), len(password))
+
+	digestDS := sha256.New()
+	for i := 0; i < 16+int(sumA[0]); i++ {
+		digestDS.Write(salt)
+	}
+
+	sequenceS := repeatBytes(digestDS.Sum(nil), len(salt))
+
+	for i := 0; i < rounds; i++ {
+		digestC := sha256.New()
+
+		if i&1 != 0 {
+			digestC.Write(sequenceP)
+		} else {
+			digestC.Write(sumA)
+		}
+
+		if i%3 != 0 {
+			digestC.Write(sequenceS)
+		}
+
+		if i%7 != 0 {
+			digestC.Write(sequenceP)
+		}
+
+		if i&1 != 0 {
+			digestC.Write(sumA)
+		} else {
+			digestC.Write(sequenceP)
+		}
+
+		sumA = digestC.Sum(nil)
+	}
+
+	const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	encoded := strings.Builder{}
+
+	encode := func(b2 byte, b1 byte, b0 byte, n int) {
+		w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+
+		for ; n > 0; n-- {
+			encoded.WriteByte(cryptAlphabet[w&0x3f])
+			w >>= 6
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		// the digest bytes are encoded in a shuffled order: (0, 10, 20), (21, 1, 11), (12, 22, 2), ...
+		a, b, c := (i*21)%30, (i*21+10)%30, (i*21+20)%30
+		encode(sumA[a], sumA[b], sumA[c], 4)
+	}
+
+	encode(0, sumA[31], sumA[30], 3)
+
+	return encoded.String()
+}
+
+// repeatBytes repeats the sequence up to the given length.
+func repeatBytes(sequence []byte, length int) []byte {
+	repeated := make([]byte, 0, length)
+
+	for len(repeated) < length {
+		repeated = append(repeated, sequence[:min(length-len(repeated), len(sequence))]...)
+	}
+
+	return repeated
+}
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestSha256Crypt(t *testing.T) {
+	// Test vectors of the SHA-crypt specification (https://www.akkadia.org/drepper/SHA-crypt.txt), with the salt
+	// already truncated to 16 characters and the rounds raised to the 1000 minimum
+	tests := []struct {
+		password string
+		salt     string
+		rounds   int
+		expected string
+	}{
+		{"Hello world!", "saltstring", 5000, "5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+		{"Hello world!", "saltstringsaltst", 10000, "3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA"},
+		{"This is just a test", "toolongsaltstrin", 5000, "Un/5jzAHMgOGZ5.mWJpuVolil07guHPvOW8mGRcvxa5"},
+		{
+			"a very much longer text to encrypt.  This one even stretches over morethan one line.", "anotherlongsalts", 1400,
+			"Rx.j8H.h8HjEDGomFU8bDkXm3XIUnzyxf12oP84Bnq1",
+		},
+		{"we have a short salt string but not a short password", "short", 77777, "JiO1O3ZpDAxGJeaDIuqCoEFysAe1mZNJRs3pw0KQRd/"},
+		{"a short string", "asaltof16chars..", 123456, "gP3VQ/6X7UUEW3HkBn2w1/Ptq2jxPyzV/cZKmF/wJvD"},
+		{"the minimum number is still observed", "roundstoolow", 1000, "yfvwcWrQ8l/K0DAWyuPMDNHpIVlTQebY9l/gL972bIC"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.salt, func(t *testing.T) {
+			if digest := sha256Crypt([]byte(test.password), []byte(test.salt), test.rounds); digest != test.expected {
+				t.Errorf("sha256Crypt(%q, %q, %d) = %q, expected %q", test.password, test.salt, test.rounds, digest, test.expected)
+			}
+		})
+	}
+}
+
+func TestAuthStringMatchesPassword(t *testing.T) {
+	const password = "Tr0ub4dor&3-correct-horse"
+
+	salt := "0123456789abcdefghij"
+	cachingSha2AuthString := hex.EncodeToString([]byte("$A$005$" + salt + sha256Crypt([]byte(password), []byte(salt), 5000)))
+
+	tests := []struct {
+		name              string
+		plugin            string
+		hexAuthString     string
+		password          string
+		expectedMatch     bool
+		expectedSupported bool
+	}{
+		{
+			name:              "native password",
+			plugin:            mysqlAuthPluginNativePassword,
+			hexAuthString:     hex.EncodeToString([]byte("*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19")),
+			password:          "password",
+			expectedMatch:     true,
+			expectedSupported: true,
+		},
+		{
+			name:              "native password mismatch",
+			plugin:            mysqlAuthPluginNativePassword,
+			hexAuthString:     hex.EncodeToString([]byte("*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19")),
+			password:          "Password",
+			expectedMatch:     false,
+			expectedSupported: true,
+		},
+		{
+			name:              "caching sha2 password",
+			plugin:            mysqlAuthPluginCachingSha2Password,
+			hexAuthString:     cachingSha2AuthString,
+			password:          password,
+			expectedMatch:     true,
+			expectedSupported: true,
+		},
+		{
+			name:              "caching sha2 password mismatch",
+			plugin:            mysqlAuthPluginCachingSha2Password,
+			hexAuthString:     cachingSha2AuthString,
+			password:          password + "!",
+			expectedMatch:     false,
+			expectedSupported: true,
+		},
+		{
+			name:              "caching sha2 password truncated",
+			plugin:            mysqlAuthPluginCachingSha2Password,
+			hexAuthString:     cachingSha2AuthString[:len(cachingSha2AuthString)-2],
+			password:          password,
+			expectedMatch:     false,
+			expectedSupported: true,
+		},
+		{
+			name:              "caching sha2 password invalid rounds",
+			plugin:            mysqlAuthPluginCachingSha2Password,
+			hexAuthString:     hex.EncodeToString([]byte("$A$0x5$" + salt + strings.Repeat("a", cachingSha2PasswordHashLength))),
+			password:          password,
+			expectedMatch:     false,
+			expectedSupported: true,
+		},
+		{
+			name:              "invalid hex",
+			plugin:            mysqlAuthPluginNativePassword,
+			hexAuthString:     "not hex",
+			password:          "password",
+			expectedMatch:     false,
+			expectedSupported: false,
+		},
+		{
+			name:              "unsupported plugin",
+			plugin:            "sha256_password",
+			hexAuthString:     cachingSha2AuthString,
+			password:          password,
+			expectedMatch:     false,
+			expectedSupported: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			match, supported := authStringMatchesPassword(test.plugin, test.hexAuthString, test.password)
+
+			if match != test.expectedMatch || supported != test.expectedSupported {
+				t.Errorf(
+					"authStringMatchesPassword() = (%t, %t), expected (%t, %t)",
+					match, supported, test.expectedMatch, test.expectedSupported,
+				)
+			}
+		})
+	}
+}
+
+func TestPasswordFingerprint(t *testing.T) {
+	fingerprint, err := passwordFingerprint("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("passwordFingerprint() error: %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		password    string
+		fingerprint string
+		expected    bool
+	}{
+		{"same password", "correct horse battery staple", fingerprint, true},
+		{"other password", "correct horse battery stapler", fingerprint, false},
+		{"other prefix", "correct horse battery staple", "md5" + strings.TrimPrefix(fingerprint, passwordFingerprintPrefix), false},
+		{"invalid salt", "correct horse battery staple", "sha256:salt:digest", false},
+		{"malformed", "correct horse battery staple", "sha256", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if match := passwordMatchesFingerprint(test.password, test.fingerprint); match != test.expected {
+				t.Errorf("passwordMatchesFingerprint(%q, %q) = %t, expected %t", test.password, test.fingerprint, match, test.expected)
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		length         int
+		charset        string
+		policy         passwordPolicy
+		expectedLength int
+		expectedError  bool
+	}{
+		{name: "default charset", length: 32, expectedLength: 32},
+		{name: "custom charset", length: 16, charset: "abc", expectedLength: 16},
+		{name: "policy length", length: 16, policy: passwordPolicy{Length: 24}, expectedLength: 24},
+		{
+			name:           "policy character classes",
+			length:         16,
+			policy:         passwordPolicy{MixedCaseCount: 2, NumberCount: 3, SpecialCharCount: 4},
+			expectedLength: 16,
+		},
+		{name: "charset missing a class", length: 16, charset: "abc", policy: passwordPolicy{NumberCount: 1}, expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			password, err := generatePassword(test.length, test.charset, test.policy)
+
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("generatePassword() = %q, expected an error", password)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("generatePassword() error: %s", err)
+			}
+
+			if len([]rune(password)) != test.expectedLength {
+				t.Errorf("generatePassword() length = %d, expected %d", len([]rune(password)), test.expectedLength)
+			}
+
+			charset := test.charset
+			if charset == "" {
+				charset = defaultPasswordCharset
+			}
+
+			counts := map[string]int{}
+
+			for _, char := range password {
+				if !strings.ContainsRune(charset, char) {
+					t.Errorf("generatePassword() = %q, %q is not in the charset", password, char)
+				}
+
+				switch {
+				case unicode.IsLower(char):
+					counts["lower"]++
+				case unicode.IsUpper(char):
+					counts["upper"]++
+				case unicode.IsDigit(char):
+					counts["digit"]++
+				default:
+					counts["special"]++
+				}
+			}
+
+			if counts["lower"] < test.policy.MixedCaseCount || counts["upper"] < test.policy.MixedCaseCount ||
+				counts["digit"] < test.policy.NumberCount || counts["special"] < test.policy.SpecialCharCount {
+				t.Errorf("generatePassword() = %q does not match the %+v policy", password, test.policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// connectionProbe is the outcome of the plan time checks of a database connection.
+type connectionProbe struct {
+	once sync.Once
+	err  error
+
+	// CurrentUser is the account the connection secret authenticates as (`user@host`)
+	CurrentUser string
+	Version     string
+	Grants      principalGrants
+	// ServerPrivileges holds the privileges the server defines (`SHOW PRIVILEGES`), including the dynamic ones
+	ServerPrivileges map[string]bool
+}
+
+// principalGrants holds the privileges of the connection account, as reported by `SHOW GRANTS FOR CURRENT_USER()`.
+type principalGrants struct {
+	// Privileges maps database patterns (`*` for global privileges) to the privileges held on them
+	Privileges map[string][]string
+	// Revoked maps databases to the global privileges partially revoked on them
+	Revoked map[string][]string
+	// PartialRevokes is set when the server `partial_revokes` setting is enabled: the `%` and `_` characters of
+	// database names are then literal, not wildcards
+	PartialRevokes bool
+	// HasRoles is set when the account was granted roles, whose privileges are not listed
+	HasRoles bool
+}
+
+var (
+	// Matches the `GRANT <privileges> ON [PROCEDURE | FUNCTION] <scope> TO <account>` lines of SHOW GRANTS.
+	showGrantsPrivilegesRegex = regexp.MustCompile(`(?i)^GRANT (.+) ON (?:(?:PROCEDURE|FUNCTION) )?(\S+) TO \S+?( WITH GRANT OPTION)?$`)
+	// Matches the `REVOKE <privileges> ON <database>.* FROM <account>` lines of SHOW GRANTS (partial revokes).
+	showGrantsPartialRevokeRegex = regexp.MustCompile(`(?i)^REVOKE (.+) ON (\S+)\.\* FROM \S+$`)
+)
+
+// probeConnection connects to the database with the connection credentials, once per cluster, secret and IAM role
+// for the provider lifetime.
+func (d *RdsDataProviderData) probeConnection(ctx context.Context, connection databaseConnection) (*connectionProbe, error) {
+	value, _ := d.connectionProbes.LoadOrStore(connection, &connectionProbe{})
+	probe := value.(*connectionProbe)
+
+	probe.once.Do(func() {
+		probe.err = probe.run(ctx, d, connection)
+	})
+
+	return probe, probe.err
+}
+
+func (p *connectionProbe) run(ctx context.Context, d *RdsDataProviderData, connection databaseConnection) error {
+	identitySqlQuery := "SELECT CURRENT_USER(), VERSION()"
+
+	identityResult, err := d.QueryStatement(ctx, connection, &rdsdata.ExecuteStatementInput{
+		Sql: &identitySqlQuery,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(identityResult.Records) != 1 || len(identityResult.Records[0]) != 2 {
+		return fmt.Errorf("unexpected `%s` result: check response returned from the AWS rdsdata service API call", identitySqlQuery)
+	}
+
+	p.CurrentUser = stringField(identityResult.Records[0][0])
+	p.Version = stringField(identityResult.Records[0][1])
+
+	// Roles and partial revokes change the privileges SHOW GRANTS reports, on the servers supporting them
+	capabilities := &serverCapabilities{}
+	capabilities.Version, _ = parseMysqlVersion(p.Version)
+
+	partialRevokes := false
+
+	if capabilities.supports(capabilityPartialRevokes) {
+		partialRevokesSqlQuery := "SELECT @@partial_revokes"
+
+		partialRevokesResult, err := d.QueryStatement(ctx, connection, &rdsdata.ExecuteStatementInput{
+			Sql: &partialRevokesSqlQuery,
+		})
+		if err != nil {
+			return err
+		}
+
+		partialRevokes = len(partialRevokesResult.Records) == 1 && len(partialRevokesResult.Records[0]) == 1 &&
+			boolField(partialRevokesResult.Records[0][0])
+	}
+
+	grantsSqlQuery := "SHOW GRANTS FOR CURRENT_USER()"
+	rolesExpanded := false
+
+	if capabilities.supports(capabilityRoles) {
+		rolesSqlQuery := "SELECT CURRENT_ROLE()"
+
+		rolesResult, err := d.QueryStatement(ctx, connection, &rdsdata.ExecuteStatementInput{
+			Sql: &rolesSqlQuery,
+		})
+		if err != nil {
+			return err
+		}
+
+		// List the privileges of the roles active in the session (`` `role`@`%`,`other`@`%` ``) too
+		if len(rolesResult.Records) == 1 && len(rolesResult.Records[0]) == 1 {
+			if roles := stringField(rolesResult.Records[0][0]); roles != "" && roles != "NONE" {
+				grantsSqlQuery += " USING " + roles
+				rolesExpanded = true
+			}
+		}
+	}
+
+	grantsResult, err := d.QueryStatement(ctx, connection, &rdsdata.ExecuteStatementInput{
+		Sql: &grantsSqlQuery,
+	})
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+
+	for _, record := range grantsResult.Records {
+		if len(record) > 0 {
+			lines = append(lines, stringField(record[0]))
+		}
+	}
+
+	p.Grants = parseShowGrants(lines, partialRevokes)
+
+	// The roles not active in the session grant no privileges to the RDS Data API statements
+	if rolesExpanded {
+		p.Grants.HasRoles = false
+	}
+
+	privilegesSqlQuery := "SHOW PRIVILEGES"
+
+	privilegesResult, err := d.QueryStatement(ctx, connection, &rdsdata.ExecuteStatementInput{
+		Sql: &privilegesSqlQuery,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.ServerPrivileges = map[string]bool{}
+
+	for _, record := range privilegesResult.Records {
+		if len(record) > 0 {
+			p.ServerPrivileges[normalizePrivilege(stringField(record[0]))] = true
+		}
+	}
+
+	return nil
+}
+
+// stringField returns the value of a string field of a RDS Data API record, empty for other types.
+func stringField(field rdsdatatypes.Field) string {
+	if value, ok := field.(*rdsdatatypes.FieldMemberStringValue); ok {
+		return value.Value
+	}
+
+	return ""
+}
+
+// boolField returns the value of a boolean (or numeric) field of a RDS Data API record, false for other types.
+func boolField(field rdsdatatypes.Field) bool {
+	switch value := field.(type) {
+	case *rdsdatatypes.FieldMemberBooleanValue:
+		return value.Value
+	case *rdsdatatypes.FieldMemberLongValue:
+		return value.Value != 0
+	case *rdsdatatypes.FieldMemberStringValue:
+		return value.Value == "1" || strings.EqualFold(value.Value, "ON")
+	}
+
+	return false
+}
+
+// parseShowGrants parses the global and database level privileges of SHOW GRANTS lines, table, column and routine
+// level privileges are ignored. The partial revokes of global privileges are parsed when the server `partial_revokes`
+// setting is enabled.
+func parseShowGrants(lines []string, partialRevokes bool) principalGrants {
+	grants := principalGrants{
+		Privileges:     map[string][]string{},
+		Revoked:        map[string][]string{},
+		PartialRevokes: partialRevokes,
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if revoke := showGrantsPartialRevokeRegex.FindStringSubmatch(line); revoke != nil {
+			if partialRevokes {
+				database := strings.Trim(revoke[2], "`'\"")
+
+				for _, privilege := range splitPrivileges(revoke[1]) {
+					grants.Revoked[database] = append(grants.Revoked[database], normalizePrivilege(privilege))
+				}
+			}
+
+			continue
+		}
+
+		match := showGrantsPrivilegesRegex.FindStringSubmatch(line)
+		if match == nil {
+			// `GRANT role1,role2 TO account`
+			if strings.HasPrefix(strings.ToUpper(line), "GRANT ") {
+				grants.HasRoles = true
+			}
+
+			continue
+		}
+
+		database, ok := strings.CutSuffix(match[2], ".*")
+		if !ok {
+			continue
+		}
+
+		database = strings.Trim(database, "`'\"")
+
+		for _, privilege := range splitPrivileges(match[1]) {
+			grants.Privileges[database] = append(grants.Privileges[database], normalizePrivilege(privilege))
+		}
+
+		if match[3] != "" {
+			grants.Privileges[database] = append(grants.Privileges[database], "GRANT OPTION")
+		}
+	}
+
+	return grants
+}
+
+// splitPrivileges splits a comma separated list of privileges, ignoring the commas of column lists
+// (`SELECT (a, b)`) and dropping the column lists.
+func splitPrivileges(list string) []string {
+	var privileges []string
+	var privilege strings.Builder
+
+	depth := 0
+
+	for _, char := range list + "," {
+		switch {
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case char == ',' && depth == 0:
+			if name := strings.TrimSpace(privilege.String()); name != "" {
+				privileges = append(privileges, name)
+			}
+			privilege.Reset()
+		case depth == 0:
+			privilege.WriteRune(char)
+		}
+	}
+
+	return privileges
+}
+
+// holds reports whether the account holds the privilege on the database (`*` for global privileges).
+func (g principalGrants) holds(privilege string, database string) bool {
+	privilege = normalizePrivilege(privilege)
+
+	for pattern, privileges := range g.Privileges {
+		switch {
+		case pattern == "*":
+			// Global privileges partially revoked on the database don't apply to it
+			if g.revoked(privilege, database) {
+				continue
+			}
+		case database == "*":
+			continue
+		case g.PartialRevokes && pattern != database:
+			continue
+		case !g.PartialRevokes && !mysqlLikeMatch(pattern, database):
+			continue
+		}
+
+		if slices.Contains(privileges, privilege) {
+			return true
+		}
+
+		if slices.Contains(privileges, "ALL") && allIncludes(privilege, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// revoked reports whether the global privilege was partially revoked on the database. Revoking ALL on a database
+// revokes every database level privilege.
+func (g principalGrants) revoked(privilege string, database string) bool {
+	revoked := g.Revoked[database]
+
+	return slices.Contains(revoked, privilege) || (slices.Contains(revoked, "ALL") && allIncludes(privilege, database))
+}
+
+// mysqlLikeMatch matches a database name against a MySQL grant pattern (`%` and `_` wildcards, `\` escapes).
+func mysqlLikeMatch(pattern string, name string) bool {
+	var expression strings.Builder
+
+	escaped := false
+
+	for _, char := range pattern {
+		switch {
+		case escaped:
+			expression.WriteString(regexp.QuoteMeta(string(char)))
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '%':
+			expression.WriteString(".*")
+		case char == '_':
+			expression.WriteString(".")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+
+	matched, err := regexp.MatchString("^"+expression.String()+"$", name)
+
+	return err == nil && matched
+}
+
+// requiredPrivilege is a privilege the connection account needs on a database (`*` for global privileges).
+type requiredPrivilege struct {
+	Privilege string
+	Database  string
+}
+
+// validateOnPlan checks the connection works and that its account holds the privileges the planned changes need,
+// when the provider `validate_on_plan` setting is enabled.
+func (d *RdsDataProviderData) validateOnPlan(ctx context.Context, connection databaseConnection, required []requiredPrivilege) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// The connection is not known yet
+	if !d.ValidateOnPlan || connection.ResourceArn == "" || connection.SecretArn == "" {
+		return diags
+	}
+
+	probe, err := d.probeConnection(ctx, connection)
+	if err != nil {
+		addOperationError(&diags, "Database connection check failed", fmt.Errorf("cannot run statements on %s: %w", connection.ResourceArn, err))
+		return diags
+	}
+
+	var missing []string
+
+	for _, privilege := range required {
+		if !probe.Grants.holds(privilege.Privilege, privilege.Database) {
+			missing = append(missing, fmt.Sprintf("%s ON %s.*", normalizePrivilege(privilege.Privilege), privilege.Database))
+		}
+	}
+
+	if len(missing) == 0 {
+		return diags
+	}
+
+	detail := fmt.Sprintf(
+		"The %s database account of the connection secret (%s) lacks the privileges the planned changes need: %s",
+		probe.CurrentUser, connection.SecretArn, strings.Join(missing, ", "),
+	)
+
+	// The privileges inherited from roles are not listed by SHOW GRANTS
+	if probe.Grants.HasRoles {
+		diags.AddWarning(
+			"Database privileges may be insufficient",
+			detail+". The account was granted roles, whose privileges could not be checked",
+		)
+		return diags
+	}
+
+	diags.AddError("Insufficient database privileges", detail)
+
+	return diags
+}
+
+// grantRequiredPrivileges returns the privileges needed to grant (or revoke) the privileges on the database.
+func grantRequiredPrivileges(database string, privileges []string) []requiredPrivilege {
+	required := []requiredPrivilege{{Privilege: "GRANT OPTION", Database: database}}
+
+	for _, privilege := range privileges {
+		// Column level privileges (`SELECT (a, b)`) need the privilege itself
+		if name, _, ok := strings.Cut(privilege, "("); ok {
+			privilege = strings.TrimSpace(name)
+		}
+
+		// Granting ALL needs every privilege of the scope, which can't be listed reliably across versions
+		if normalized := normalizePrivilege(privilege); normalized == "USAGE" || normalized == "ALL" {
+			continue
+		}
+
+		required = append(required, requiredPrivilege{Privilege: privilege, Database: database})
+	}
+
+	return required
+}
+
+// accountRequiredPrivileges returns the privileges needed to create, alter, rename and drop accounts.
+var accountRequiredPrivileges = []requiredPrivilege{{Privilege: "CREATE USER", Database: "*"}}
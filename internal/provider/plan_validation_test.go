@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseShowGrants(t *testing.T) {
+	tests := []struct {
+		name           string
+		lines          []string
+		partialRevokes bool
+		expected       principalGrants
+	}{
+		{
+			name:     "usage only",
+			lines:    []string{"GRANT USAGE ON *.* TO `app`@`%`"},
+			expected: principalGrants{Privileges: map[string][]string{"*": {"USAGE"}}, Revoked: map[string][]string{}},
+		},
+		{
+			name: "global and database privileges",
+			lines: []string{
+				"GRANT SELECT, RELOAD, CREATE USER ON *.* TO `admin`@`%` WITH GRANT OPTION",
+				"GRANT ALL PRIVILEGES ON `app\\_%`.* TO `admin`@`%`",
+			},
+			expected: principalGrants{
+				Privileges: map[string][]string{
+					"*":       {"SELECT", "RELOAD", "CREATE USER", "GRANT OPTION"},
+					"app\\_%": {"ALL"},
+				},
+				Revoked: map[string][]string{},
+			},
+		},
+		{
+			name: "dynamic privileges",
+			lines: []string{
+				"GRANT BACKUP_ADMIN,ROLE_ADMIN ON *.* TO `admin`@`%`",
+			},
+			expected: principalGrants{Privileges: map[string][]string{"*": {"BACKUP_ADMIN", "ROLE_ADMIN"}}, Revoked: map[string][]string{}},
+		},
+		{
+			name: "table, column and routine privileges are ignored",
+			lines: []string{
+				"GRANT SELECT (`id`, `name`), UPDATE (`name`) ON `app`.`users` TO `admin`@`%`",
+				"GRANT EXECUTE ON PROCEDURE `app`.`cleanup` TO `admin`@`%`",
+			},
+			expected: principalGrants{Privileges: map[string][]string{}, Revoked: map[string][]string{}},
+		},
+		{
+			name: "roles",
+			lines: []string{
+				"GRANT USAGE ON *.* TO `admin`@`%`",
+				"GRANT `rds_superuser_role`@`%` TO `admin`@`%`",
+			},
+			expected: principalGrants{
+				Privileges: map[string][]string{"*": {"USAGE"}},
+				Revoked:    map[string][]string{},
+				HasRoles:   true,
+			},
+		},
+		{
+			name: "partial revokes",
+			lines: []string{
+				"GRANT SELECT, INSERT ON *.* TO `admin`@`%`",
+				"REVOKE INSERT ON `mysql`.* FROM `admin`@`%`",
+			},
+			partialRevokes: true,
+			expected: principalGrants{
+				Privileges:     map[string][]string{"*": {"SELECT", "INSERT"}},
+				Revoked:        map[string][]string{"mysql": {"INSERT"}},
+				PartialRevokes: true,
+			},
+		},
+		{
+			name: "partial revokes disabled",
+			lines: []string{
+				"GRANT SELECT ON *.* TO `admin`@`%`",
+				"REVOKE SELECT ON `mysql`.* FROM `admin`@`%`",
+			},
+			expected: principalGrants{Privileges: map[string][]string{"*": {"SELECT"}}, Revoked: map[string][]string{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if grants := parseShowGrants(test.lines, test.partialRevokes); !reflect.DeepEqual(grants, test.expected) {
+				t.Errorf("parseShowGrants() = %+v, expected %+v", grants, test.expected)
+			}
+		})
+	}
+}
+
+func TestSplitPrivileges(t *testing.T) {
+	tests := []struct {
+		list     string
+		expected []string
+	}{
+		{"SELECT", []string{"SELECT"}},
+		{"SELECT, INSERT,UPDATE", []string{"SELECT", "INSERT", "UPDATE"}},
+		{"SELECT (`a`, `b`), INSERT (`c`)", []string{"SELECT", "INSERT"}},
+		{"CREATE TEMPORARY TABLES, LOCK TABLES", []string{"CREATE TEMPORARY TABLES", "LOCK TABLES"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.list, func(t *testing.T) {
+			if privileges := splitPrivileges(test.list); !slices.Equal(privileges, test.expected) {
+				t.Errorf("splitPrivileges(%q) = %q, expected %q", test.list, privileges, test.expected)
+			}
+		})
+	}
+}
+
+func TestPrincipalGrantsHolds(t *testing.T) {
+	grants := parseShowGrants([]string{
+		"GRANT RELOAD, CREATE USER ON *.* TO `admin`@`%`",
+		"GRANT ALL PRIVILEGES ON `app\\_%`.* TO `admin`@`%` WITH GRANT OPTION",
+		"GRANT SELECT ON `reporting`.* TO `admin`@`%`",
+	}, false)
+
+	tests := []struct {
+		privilege string
+		database  string
+		expected  bool
+	}{
+		{"CREATE USER", "*", true},
+		{"reload", "*", true},
+		{"RELOAD", "reporting", true},
+		{"SELECT", "*", false},
+		{"SELECT", "reporting", true},
+		{"INSERT", "reporting", false},
+		{"all privileges", "app_orders", true},
+		{"INSERT", "app_orders", true},
+		{"GRANT OPTION", "app_orders", true},
+		{"GRANT OPTION", "reporting", false},
+		// `\_` only matches an underscore
+		{"INSERT", "appxorders", false},
+		// ALL on a database does not include the global only privileges
+		{"SUPER", "app_orders", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.privilege+" "+test.database, func(t *testing.T) {
+			if holds := grants.holds(test.privilege, test.database); holds != test.expected {
+				t.Errorf("holds(%q, %q) = %t, expected %t", test.privilege, test.database, holds, test.expected)
+			}
+		})
+	}
+}
+
+func TestPrincipalGrantsHoldsPartialRevokes(t *testing.T) {
+	grants := parseShowGrants([]string{
+		"GRANT SELECT, INSERT, DROP ON *.* TO `admin`@`%`",
+		"GRANT CREATE ON `app_%`.* TO `admin`@`%`",
+		"REVOKE INSERT ON `mysql`.* FROM `admin`@`%`",
+		"REVOKE ALL PRIVILEGES ON `audit`.* FROM `admin`@`%`",
+	}, true)
+
+	tests := []struct {
+		privilege string
+		database  string
+		expected  bool
+	}{
+		{"INSERT", "*", true},
+		{"INSERT", "app", true},
+		{"INSERT", "mysql", false},
+		{"SELECT", "mysql", true},
+		{"DROP", "audit", false},
+		// Database names are literal with partial revokes
+		{"CREATE", "app_%", true},
+		{"CREATE", "app_orders", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.privilege+" "+test.database, func(t *testing.T) {
+			if holds := grants.holds(test.privilege, test.database); holds != test.expected {
+				t.Errorf("holds(%q, %q) = %t, expected %t", test.privilege, test.database, holds, test.expected)
+			}
+		})
+	}
+}
+
+func TestMysqlLikeMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"app", "app", true},
+		{"app", "App", false},
+		{"app%", "app_orders", true},
+		{"app_", "app1", true},
+		{"app_", "app12", false},
+		{"app\\_%", "app_orders", true},
+		{"app\\_%", "appxorders", false},
+		{"a.b", "axb", false},
+		{"%", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.name, func(t *testing.T) {
+			if matched := mysqlLikeMatch(test.pattern, test.name); matched != test.expected {
+				t.Errorf("mysqlLikeMatch(%q, %q) = %t, expected %t", test.pattern, test.name, matched, test.expected)
+			}
+		})
+	}
+}
+
+func TestGrantRequiredPrivileges(t *testing.T) {
+	required := grantRequiredPrivileges("app", []string{"SELECT (id, name)", "INSERT", "ALL PRIVILEGES", "USAGE"})
+
+	expected := []requiredPrivilege{
+		{Privilege: "GRANT OPTION", Database: "app"},
+		{Privilege: "SELECT", Database: "app"},
+		{Privilege: "INSERT", Database: "app"},
+	}
+
+	if !slices.Equal(required, expected) {
+		t.Errorf("grantRequiredPrivileges() = %+v, expected %+v", required, expected)
+	}
+}
@@ -0,0 +1,314 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The MySQL and RDS system accounts, which can never be managed.
+var builtinProtectedAccounts = []string{"rdsadmin", "mysql.sys", "mysql.session", "mysql.infoschema", "sys"}
+
+// The MySQL and RDS system databases, on which privileges can never be managed.
+var builtinProtectedDatabases = []string{"rdsadmin", "mysql.sys"}
+
+// RdsDataProviderPolicyModel describes the provider `policy` block data model.
+type RdsDataProviderPolicyModel struct {
+	ProtectedAccounts   types.List `tfsdk:"protected_accounts"`
+	ProtectedDatabases  types.List `tfsdk:"protected_databases"`
+	ForbiddenPrivileges types.List `tfsdk:"forbidden_privileges"`
+	AllowWildcardHosts  types.Bool `tfsdk:"allow_wildcard_hosts"`
+	AllowSelfManagement types.Bool `tfsdk:"allow_self_management"`
+}
+
+// policyBlockSchema returns the provider `policy` block schema.
+func policyBlockSchema() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Guardrails evaluated when planning every resource: a plan violating them fails with an error. " +
+			"Patterns are globs (`*` and `?` wildcards) or, when wrapped in slashes (`/^app_.*$/`), regular expressions",
+		Attributes: map[string]schema.Attribute{
+			"protected_accounts": schema.ListAttribute{
+				MarkdownDescription: "Patterns of the MySQL accounts that cannot be managed, matched against both `user` and " +
+					"`user@host` (e.g. `admin`, `*@%`). The `rdsadmin`, `mysql.sys`, `mysql.session`, `mysql.infoschema` and `sys` " +
+					"system accounts are always protected",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"protected_databases": schema.ListAttribute{
+				MarkdownDescription: "Patterns of the databases privileges cannot be granted on (e.g. `mysql`, `billing_*`). " +
+					"The `rdsadmin` and `mysql.sys` system databases are always protected",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"forbidden_privileges": schema.ListAttribute{
+				MarkdownDescription: "The privileges that cannot be granted, either anywhere (`SUPER`, `GRANT OPTION`) or on a " +
+					"scope (`ALL ON *.*`, `DELETE ON billing_*.*`). The `*.*` scope only matches global grants. " +
+					"`ALL` grants are considered to include every privilege available at their scope",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"allow_wildcard_hosts": schema.BoolAttribute{
+				MarkdownDescription: "Whether accounts can use host values matching several hosts (`%`, `%.example.com`, " +
+					"`10.0.%`, netmasks and CIDR blocks). Defaults to `true`",
+				Optional: true,
+			},
+			"allow_self_management": schema.BoolAttribute{
+				MarkdownDescription: "Whether resources can manage the MySQL user the provider authenticates with (the `username` " +
+					"stored in the database secret). Changing its password or dropping it cuts the provider and the RDS Data API " +
+					"off the database. Only the user name is compared: the accounts of every host of that user are protected. " +
+					"Plans fail when the database secret can't be read. Defaults to `false`",
+				Optional: true,
+			},
+		},
+	}
+}
+
+// namePattern is a glob (`*` and `?` wildcards) or a regular expression (wrapped in slashes) matching MySQL names.
+type namePattern struct {
+	Pattern string
+	regex   *regexp.Regexp
+}
+
+func newNamePattern(pattern string) (namePattern, error) {
+	expression := ""
+
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		expression = pattern[1 : len(pattern)-1]
+	} else {
+		var glob strings.Builder
+
+		for _, char := range pattern {
+			switch char {
+			case '*':
+				glob.WriteString(".*")
+			case '?':
+				glob.WriteString(".")
+			default:
+				glob.WriteString(regexp.QuoteMeta(string(char)))
+			}
+		}
+
+		expression = "^" + glob.String() + "$"
+	}
+
+	regex, err := regexp.Compile(expression)
+	if err != nil {
+		return namePattern{}, fmt.Errorf("%q is not a valid pattern: %w", pattern, err)
+	}
+
+	return namePattern{Pattern: pattern, regex: regex}, nil
+}
+
+func (p namePattern) matches(name string) bool {
+	return p.regex.MatchString(name)
+}
+
+// forbiddenPrivilege is a privilege that cannot be granted, optionally only on some databases.
+type forbiddenPrivilege struct {
+	Rule      string
+	Privilege string
+	// Database is nil for privileges forbidden on every scope, `*` only matches global grants
+	Database *namePattern
+}
+
+func newForbiddenPrivilege(rule string) (forbiddenPrivilege, error) {
+	privilege, scope, scoped := cutFold(rule, " ON ")
+
+	forbidden := forbiddenPrivilege{
+		Rule:      rule,
+		Privilege: normalizePrivilege(privilege),
+	}
+
+	if forbidden.Privilege == "" {
+		return forbiddenPrivilege{}, fmt.Errorf("%q is not a valid privilege rule, expected `PRIVILEGE` or `PRIVILEGE ON database.*`", rule)
+	}
+
+	if scoped {
+		database, ok := strings.CutSuffix(strings.TrimSpace(scope), ".*")
+		if !ok || database == "" {
+			return forbiddenPrivilege{}, fmt.Errorf("%q has an invalid scope %q, expected `*.*` or `database.*`", rule, scope)
+		}
+
+		pattern, err := newNamePattern(database)
+		if err != nil {
+			return forbiddenPrivilege{}, err
+		}
+
+		forbidden.Database = &pattern
+	}
+
+	return forbidden, nil
+}
+
+// matches reports whether granting the privilege on the database (`*` for global grants) violates the rule.
+func (f forbiddenPrivilege) matches(privilege string, database string) bool {
+	if f.Database != nil {
+		if f.Database.Pattern == "*" && database != "*" {
+			return false
+		}
+
+		if f.Database.Pattern != "*" && !f.Database.matches(database) {
+			return false
+		}
+	}
+
+	if privilege == f.Privilege {
+		return true
+	}
+
+	return privilege == "ALL" && allIncludes(f.Privilege, database)
+}
+
+// normalizePrivilege returns the canonical form of a privilege name (upper case, `ALL PRIVILEGES` as `ALL`).
+func normalizePrivilege(privilege string) string {
+	normalized := strings.ToUpper(strings.Join(strings.Fields(privilege), " "))
+
+	if normalized == "ALL PRIVILEGES" {
+		return "ALL"
+	}
+
+	return normalized
+}
+
+// cutFold is strings.Cut with a case-insensitive separator.
+func cutFold(s string, sep string) (string, string, bool) {
+	if i := strings.Index(strings.ToUpper(s), strings.ToUpper(sep)); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+// guardrailPolicy is the parsed provider `policy` block, the system accounts and databases are always protected.
+type guardrailPolicy struct {
+	ProtectedAccounts   []namePattern
+	ProtectedDatabases  []namePattern
+	ForbiddenPrivileges []forbiddenPrivilege
+	AllowWildcardHosts  bool
+	AllowSelfManagement bool
+}
+
+// newGuardrailPolicy parses the provider `policy` block (model is nil when the block is not configured).
+func newGuardrailPolicy(ctx context.Context, model *RdsDataProviderPolicyModel) (*guardrailPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := &guardrailPolicy{AllowWildcardHosts: true}
+
+	for _, account := range builtinProtectedAccounts {
+		pattern, _ := newNamePattern(account)
+		policy.ProtectedAccounts = append(policy.ProtectedAccounts, pattern)
+	}
+
+	for _, database := range builtinProtectedDatabases {
+		pattern, _ := newNamePattern(database)
+		policy.ProtectedDatabases = append(policy.ProtectedDatabases, pattern)
+	}
+
+	if model == nil {
+		return policy, diags
+	}
+
+	if !model.AllowWildcardHosts.IsNull() {
+		policy.AllowWildcardHosts = model.AllowWildcardHosts.ValueBool()
+	}
+
+	policy.AllowSelfManagement = model.AllowSelfManagement.ValueBool()
+
+	patternLists := []struct {
+		Attribute string
+		List      types.List
+		Patterns  *[]namePattern
+	}{
+		{"protected_accounts", model.ProtectedAccounts, &policy.ProtectedAccounts},
+		{"protected_databases", model.ProtectedDatabases, &policy.ProtectedDatabases},
+	}
+
+	for _, patternList := range patternLists {
+		var values []string
+
+		diags.Append(patternList.List.ElementsAs(ctx, &values, false)...)
+
+		for _, value := range values {
+			pattern, err := newNamePattern(value)
+			if err != nil {
+				diags.AddAttributeError(path.Root("policy").AtName(patternList.Attribute), "Invalid Policy Pattern", err.Error())
+				continue
+			}
+
+			*patternList.Patterns = append(*patternList.Patterns, pattern)
+		}
+	}
+
+	var rules []string
+
+	diags.Append(model.ForbiddenPrivileges.ElementsAs(ctx, &rules, false)...)
+
+	for _, rule := range rules {
+		forbidden, err := newForbiddenPrivilege(rule)
+		if err != nil {
+			diags.AddAttributeError(path.Root("policy").AtName("forbidden_privileges"), "Invalid Policy Privilege Rule", err.Error())
+			continue
+		}
+
+		policy.ForbiddenPrivileges = append(policy.ForbiddenPrivileges, forbidden)
+	}
+
+	return policy, diags
+}
+
+// checkAccount ensures the MySQL account can be managed.
+func (p *guardrailPolicy) checkAccount(user string, host string) error {
+	if p == nil {
+		return nil
+	}
+
+	account := user + "@" + host
+
+	for _, pattern := range p.ProtectedAccounts {
+		if pattern.matches(user) || pattern.matches(account) {
+			return fmt.Errorf("the MySQL account '%s'@'%s' is protected by the %q pattern", user, host, pattern.Pattern)
+		}
+	}
+
+	if p.AllowWildcardHosts {
+		return nil
+	}
+
+	hostPattern, err := parseHostPattern(host)
+	if err == nil && hostPattern.IsWildcard() {
+		return fmt.Errorf("the host %q matches several hosts, which the provider policy forbids (`allow_wildcard_hosts`)", host)
+	}
+
+	return nil
+}
+
+// checkGrant ensures the privileges can be granted on the database (`*` for global grants).
+func (p *guardrailPolicy) checkGrant(database string, privileges []string) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, pattern := range p.ProtectedDatabases {
+		if pattern.matches(database) {
+			return fmt.Errorf("the %q database is protected by the %q pattern", database, pattern.Pattern)
+		}
+	}
+
+	for _, privilege := range privileges {
+		privilege = normalizePrivilege(privilege)
+
+		for _, forbidden := range p.ForbiddenPrivileges {
+			if forbidden.matches(privilege, database) {
+				return fmt.Errorf("granting %s on %s.* is forbidden by the %q provider policy rule", privilege, database, forbidden.Rule)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestNewNamePattern(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		name          string
+		expected      bool
+		expectedError bool
+	}{
+		{pattern: "app", name: "app", expected: true},
+		{pattern: "app", name: "app2", expected: false},
+		{pattern: "app_*", name: "app_orders", expected: true},
+		{pattern: "app_*", name: "appxorders", expected: false},
+		{pattern: "app?", name: "app1", expected: true},
+		{pattern: "app?", name: "app12", expected: false},
+		{pattern: "mysql.sys", name: "mysqlxsys", expected: false},
+		{pattern: "admin@%", name: "admin@%", expected: true},
+		{pattern: "/^ops-[0-9]+$/", name: "ops-42", expected: true},
+		{pattern: "/^ops-[0-9]+$/", name: "ops-x", expected: false},
+		{pattern: "/ops/", name: "devops-admin", expected: true},
+		{pattern: "/", name: "/", expected: true},
+		{pattern: "/(/", expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.name, func(t *testing.T) {
+			pattern, err := newNamePattern(test.pattern)
+
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("newNamePattern(%q) succeeded, expected an error", test.pattern)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("newNamePattern(%q) error: %s", test.pattern, err)
+			}
+
+			if matches := pattern.matches(test.name); matches != test.expected {
+				t.Errorf("newNamePattern(%q).matches(%q) = %t, expected %t", test.pattern, test.name, matches, test.expected)
+			}
+		})
+	}
+}
+
+func TestForbiddenPrivilegeMatches(t *testing.T) {
+	tests := []struct {
+		rule          string
+		privilege     string
+		database      string
+		expected      bool
+		expectedError bool
+	}{
+		{rule: "SUPER", privilege: "SUPER", database: "*", expected: true},
+		{rule: "super", privilege: "SUPER", database: "*", expected: true},
+		{rule: "SUPER", privilege: "ALL", database: "*", expected: true},
+		// ALL on a database does not include the global only privileges
+		{rule: "SUPER", privilege: "ALL", database: "app", expected: false},
+		{rule: "DROP", privilege: "ALL", database: "app", expected: true},
+		{rule: "GRANT OPTION", privilege: "ALL", database: "*", expected: false},
+		{rule: "PROXY", privilege: "ALL", database: "*", expected: false},
+		{rule: "DROP ON *.*", privilege: "DROP", database: "*", expected: true},
+		{rule: "DROP ON *.*", privilege: "DROP", database: "app", expected: false},
+		{rule: "DROP on prod_*.*", privilege: "DROP", database: "prod_orders", expected: true},
+		{rule: "DROP ON prod_*.*", privilege: "DROP", database: "staging_orders", expected: false},
+		{rule: "DROP ON prod_*.*", privilege: "SELECT", database: "prod_orders", expected: false},
+		{rule: "", expectedError: true},
+		{rule: "DROP ON prod", expectedError: true},
+		{rule: "DROP ON .*", expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule+" "+test.privilege+" "+test.database, func(t *testing.T) {
+			forbidden, err := newForbiddenPrivilege(test.rule)
+
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("newForbiddenPrivilege(%q) succeeded, expected an error", test.rule)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("newForbiddenPrivilege(%q) error: %s", test.rule, err)
+			}
+
+			if matches := forbidden.matches(test.privilege, test.database); matches != test.expected {
+				t.Errorf("%q matches(%q, %q) = %t, expected %t", test.rule, test.privilege, test.database, matches, test.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizePrivilege(t *testing.T) {
+	tests := []struct {
+		privilege string
+		expected  string
+	}{
+		{"select", "SELECT"},
+		{"  create   temporary tables ", "CREATE TEMPORARY TABLES"},
+		{"all privileges", "ALL"},
+		{"ALL", "ALL"},
+		{"backup_admin", "BACKUP_ADMIN"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.privilege, func(t *testing.T) {
+			if normalized := normalizePrivilege(test.privilege); normalized != test.expected {
+				t.Errorf("normalizePrivilege(%q) = %q, expected %q", test.privilege, normalized, test.expected)
+			}
+		})
+	}
+}
+
+func TestGuardrailPolicy(t *testing.T) {
+	protectedAccount, _ := newNamePattern("admin@%")
+	protectedDatabase, _ := newNamePattern("audit_*")
+	forbidden, _ := newForbiddenPrivilege("SUPER")
+
+	policy := &guardrailPolicy{
+		ProtectedAccounts:   []namePattern{protectedAccount},
+		ProtectedDatabases:  []namePattern{protectedDatabase},
+		ForbiddenPrivileges: []forbiddenPrivilege{forbidden},
+	}
+
+	accountTests := []struct {
+		user          string
+		host          string
+		expectedError bool
+	}{
+		{user: "app", host: "10.0.0.1"},
+		{user: "admin", host: "10.0.0.1"},
+		{user: "admin", host: "%", expectedError: true},
+		{user: "app", host: "10.0.%", expectedError: true},
+		{user: "app", host: "%.example.com", expectedError: true},
+	}
+
+	for _, test := range accountTests {
+		t.Run(test.user+"@"+test.host, func(t *testing.T) {
+			if err := policy.checkAccount(test.user, test.host); (err != nil) != test.expectedError {
+				t.Errorf("checkAccount(%q, %q) = %v, expected an error: %t", test.user, test.host, err, test.expectedError)
+			}
+		})
+	}
+
+	grantTests := []struct {
+		database      string
+		privileges    []string
+		expectedError bool
+	}{
+		{database: "app", privileges: []string{"SELECT", "INSERT"}},
+		{database: "app", privileges: []string{"ALL PRIVILEGES"}},
+		{database: "*", privileges: []string{"all privileges"}, expectedError: true},
+		{database: "*", privileges: []string{"PROCESS", "super"}, expectedError: true},
+		{database: "audit_log", privileges: []string{"SELECT"}, expectedError: true},
+	}
+
+	for _, test := range grantTests {
+		t.Run(test.database, func(t *testing.T) {
+			if err := policy.checkGrant(test.database, test.privileges); (err != nil) != test.expectedError {
+				t.Errorf("checkGrant(%q, %q) = %v, expected an error: %t", test.database, test.privileges, err, test.expectedError)
+			}
+		})
+	}
+
+	var disabled *guardrailPolicy
+
+	if err := disabled.checkAccount("rdsadmin", "%"); err != nil {
+		t.Errorf("nil policy checkAccount() = %v, expected no error", err)
+	}
+}
@@ -0,0 +1,351 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// privilegeScope is a set of levels a privilege can be granted at.
+type privilegeScope int
+
+const (
+	// `ON *.*`
+	privilegeScopeGlobal privilegeScope = 1 << iota
+	// `ON db.*`
+	privilegeScopeDatabase
+	// `ON db.table`
+	privilegeScopeTable
+	// `SELECT (column) ON db.table`
+	privilegeScopeColumn
+	// `ON PROCEDURE db.routine`, `ON FUNCTION db.routine`
+	privilegeScopeRoutine
+	// `ON user@host`
+	privilegeScopeAccount
+)
+
+// The names of the privilege scopes, used in the validation diagnostics.
+var privilegeScopeNames = []struct {
+	Scope privilegeScope
+	Name  string
+}{
+	{privilegeScopeGlobal, "global (`*.*`)"},
+	{privilegeScopeDatabase, "database (`db.*`)"},
+	{privilegeScopeTable, "table"},
+	{privilegeScopeColumn, "column"},
+	{privilegeScopeRoutine, "routine"},
+	{privilegeScopeAccount, "account (`ON user@host`)"},
+}
+
+func (s privilegeScope) String() string {
+	var names []string
+
+	for _, scope := range privilegeScopeNames {
+		if s&scope.Scope != 0 {
+			names = append(names, scope.Name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// privilegeDefinition describes a privilege of the catalog.
+type privilegeDefinition struct {
+	Scopes privilegeScope
+	// Since is the first MySQL version defining the privilege
+	Since mysqlVersion
+	// Until is the first MySQL version no longer defining the privilege, zero when it is still defined
+	Until mysqlVersion
+}
+
+const (
+	privilegeScopesAll     = privilegeScopeGlobal | privilegeScopeDatabase | privilegeScopeTable | privilegeScopeRoutine
+	privilegeScopesTable   = privilegeScopeGlobal | privilegeScopeDatabase | privilegeScopeTable
+	privilegeScopesColumn  = privilegeScopesTable | privilegeScopeColumn
+	privilegeScopesRoutine = privilegeScopeGlobal | privilegeScopeDatabase | privilegeScopeRoutine
+)
+
+var (
+	mysqlVersion80 = mysqlVersion{8, 0, 0}
+
+	// The static privileges of every MySQL version, and the dynamic privileges of MySQL 8.0 (always global)
+	globalPrivilege        = privilegeDefinition{Scopes: privilegeScopeGlobal}
+	databasePrivilege      = privilegeDefinition{Scopes: privilegeScopeGlobal | privilegeScopeDatabase}
+	dynamicPrivilege       = privilegeDefinition{Scopes: privilegeScopeGlobal, Since: mysqlVersion80}
+	aurora2GlobalPrivilege = privilegeDefinition{Scopes: privilegeScopeGlobal, Until: mysqlVersion80}
+)
+
+// dynamicPrivilegeSince returns the definition of a dynamic privilege introduced by a MySQL 8.0 release.
+func dynamicPrivilegeSince(patch int) privilegeDefinition {
+	return privilegeDefinition{Scopes: privilegeScopeGlobal, Since: mysqlVersion{8, 0, patch}}
+}
+
+// The privileges of the MySQL versions supported by Aurora MySQL 2 (5.7) and 3 (8.0), including the Aurora ones.
+var privilegeCatalog = map[string]privilegeDefinition{
+	"ALL":                     {Scopes: privilegeScopesAll},
+	"ALTER":                   {Scopes: privilegeScopesTable},
+	"ALTER ROUTINE":           {Scopes: privilegeScopesRoutine},
+	"CREATE":                  {Scopes: privilegeScopesTable},
+	"CREATE ROLE":             {Scopes: privilegeScopeGlobal, Since: mysqlVersion80},
+	"CREATE ROUTINE":          databasePrivilege,
+	"CREATE TABLESPACE":       globalPrivilege,
+	"CREATE TEMPORARY TABLES": databasePrivilege,
+	"CREATE USER":             globalPrivilege,
+	"CREATE VIEW":             {Scopes: privilegeScopesTable},
+	"DELETE":                  {Scopes: privilegeScopesTable},
+	"DROP":                    {Scopes: privilegeScopesTable},
+	"DROP ROLE":               {Scopes: privilegeScopeGlobal, Since: mysqlVersion80},
+	"EVENT":                   databasePrivilege,
+	"EXECUTE":                 {Scopes: privilegeScopesRoutine},
+	"FILE":                    globalPrivilege,
+	"GRANT OPTION":            {Scopes: privilegeScopesAll},
+	"INDEX":                   {Scopes: privilegeScopesTable},
+	"INSERT":                  {Scopes: privilegeScopesColumn},
+	"LOCK TABLES":             databasePrivilege,
+	"PROCESS":                 globalPrivilege,
+	"PROXY":                   {Scopes: privilegeScopeAccount},
+	"REFERENCES":              {Scopes: privilegeScopesColumn},
+	"RELOAD":                  globalPrivilege,
+	"REPLICATION CLIENT":      globalPrivilege,
+	"REPLICATION SLAVE":       globalPrivilege,
+	"SELECT":                  {Scopes: privilegeScopesColumn},
+	"SHOW DATABASES":          globalPrivilege,
+	"SHOW VIEW":               {Scopes: privilegeScopesTable},
+	"SHUTDOWN":                globalPrivilege,
+	"SUPER":                   globalPrivilege,
+	"TRIGGER":                 {Scopes: privilegeScopesTable},
+	"UPDATE":                  {Scopes: privilegeScopesColumn},
+	"USAGE":                   {Scopes: privilegeScopesAll},
+
+	"APPLICATION_PASSWORD_ADMIN":   dynamicPrivilegeSince(14),
+	"AUDIT_ABORT_EXEMPT":           dynamicPrivilegeSince(28),
+	"AUDIT_ADMIN":                  dynamicPrivilege,
+	"AUTHENTICATION_POLICY_ADMIN":  dynamicPrivilegeSince(27),
+	"BACKUP_ADMIN":                 dynamicPrivilege,
+	"BINLOG_ADMIN":                 dynamicPrivilege,
+	"BINLOG_ENCRYPTION_ADMIN":      dynamicPrivilege,
+	"CLONE_ADMIN":                  dynamicPrivilegeSince(17),
+	"CONNECTION_ADMIN":             dynamicPrivilege,
+	"ENCRYPTION_KEY_ADMIN":         dynamicPrivilege,
+	"FIREWALL_EXEMPT":              dynamicPrivilegeSince(27),
+	"FLUSH_OPTIMIZER_COSTS":        dynamicPrivilegeSince(23),
+	"FLUSH_STATUS":                 dynamicPrivilegeSince(23),
+	"FLUSH_TABLES":                 dynamicPrivilegeSince(23),
+	"FLUSH_USER_RESOURCES":         dynamicPrivilegeSince(23),
+	"GROUP_REPLICATION_ADMIN":      dynamicPrivilege,
+	"GROUP_REPLICATION_STREAM":     dynamicPrivilegeSince(26),
+	"INNODB_REDO_LOG_ARCHIVE":      dynamicPrivilegeSince(17),
+	"INNODB_REDO_LOG_ENABLE":       dynamicPrivilegeSince(21),
+	"PASSWORDLESS_USER_ADMIN":      dynamicPrivilegeSince(27),
+	"PERSIST_RO_VARIABLES_ADMIN":   dynamicPrivilege,
+	"REPLICATION_APPLIER":          dynamicPrivilegeSince(18),
+	"REPLICATION_SLAVE_ADMIN":      dynamicPrivilege,
+	"RESOURCE_GROUP_ADMIN":         dynamicPrivilege,
+	"RESOURCE_GROUP_USER":          dynamicPrivilege,
+	"ROLE_ADMIN":                   dynamicPrivilege,
+	"SENSITIVE_VARIABLES_OBSERVER": dynamicPrivilegeSince(29),
+	"SERVICE_CONNECTION_ADMIN":     dynamicPrivilege,
+	"SESSION_VARIABLES_ADMIN":      dynamicPrivilege,
+	"SET_USER_ID":                  dynamicPrivilege,
+	"SHOW_ROUTINE":                 dynamicPrivilegeSince(20),
+	"SYSTEM_USER":                  dynamicPrivilegeSince(16),
+	"SYSTEM_VARIABLES_ADMIN":       dynamicPrivilege,
+	"TABLE_ENCRYPTION_ADMIN":       dynamicPrivilegeSince(16),
+	"XA_RECOVER_ADMIN":             dynamicPrivilege,
+
+	// Aurora MySQL 3 dynamic privileges
+	"AWS_COMPREHEND_ACCESS": dynamicPrivilege,
+	"AWS_LAMBDA_ACCESS":     dynamicPrivilege,
+	"AWS_LOAD_S3_ACCESS":    dynamicPrivilege,
+	"AWS_SAGEMAKER_ACCESS":  dynamicPrivilege,
+	"AWS_SELECT_S3_ACCESS":  dynamicPrivilege,
+
+	// Aurora MySQL 2 privileges, replaced by the dynamic ones in Aurora MySQL 3
+	"INVOKE COMPREHEND": aurora2GlobalPrivilege,
+	"INVOKE LAMBDA":     aurora2GlobalPrivilege,
+	"INVOKE SAGEMAKER":  aurora2GlobalPrivilege,
+	"LOAD FROM S3":      aurora2GlobalPrivilege,
+	"SELECT INTO S3":    aurora2GlobalPrivilege,
+}
+
+// Matches the names server components and plugins may register dynamic privileges with.
+var dynamicPrivilegeNameRegex = regexp.MustCompile(`^[A-Z0-9]+(_[A-Z0-9]+)+$`)
+
+// globalOnlyPrivilege reports whether the privilege only exists at the global (`*.*`) level, so `ALL` on a database
+// does not include it.
+func globalOnlyPrivilege(privilege string) bool {
+	definition, ok := privilegeCatalog[privilege]
+
+	return ok && definition.Scopes == privilegeScopeGlobal
+}
+
+// allIncludes reports whether `ALL` granted on the database (`*` for global privileges) includes the privilege:
+// every privilege of its level, but GRANT OPTION and PROXY.
+func allIncludes(privilege string, database string) bool {
+	if privilege == "GRANT OPTION" || privilege == "PROXY" {
+		return false
+	}
+
+	return database == "*" || !globalOnlyPrivilege(privilege)
+}
+
+// validatePrivileges checks the privileges granted on every table of the database (`*` for global privileges)
+// against the privilege catalog. The MySQL version of the connection cluster is detected to check the privileges
+// depending on it. With the provider `validate_on_plan` setting, the privileges are also checked against the live
+// `SHOW PRIVILEGES` listing, which includes the dynamic privileges registered by server components.
+func (d *RdsDataProviderData) validatePrivileges(ctx context.Context, connection databaseConnection, database string, privileges []string, attributePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	scope := privilegeScopeDatabase
+	if database == "*" {
+		scope = privilegeScopeGlobal
+	}
+
+	// The server version is only detected for the privileges depending on it
+	var capabilities *serverCapabilities
+
+	versionDetected := false
+	detectCapabilities := func() *serverCapabilities {
+		if !versionDetected {
+			capabilities = d.detectedCapabilities(ctx, connection)
+			versionDetected = true
+		}
+
+		return capabilities
+	}
+
+	// Connection failures are reported by the plan validation itself
+	var serverPrivileges map[string]bool
+
+	if d.ValidateOnPlan && connection.ResourceArn != "" && connection.SecretArn != "" {
+		if probe, err := d.probeConnection(ctx, connection); err == nil {
+			serverPrivileges = probe.ServerPrivileges
+		}
+	}
+
+	for _, privilege := range privileges {
+		name, _, columns := strings.Cut(privilege, "(")
+		name = normalizePrivilege(name)
+
+		if columns {
+			diags.AddAttributeError(attributePath, "Invalid privilege", fmt.Sprintf(
+				"%q is a column level privilege, which can only be granted on a table, not on `%s.*`", privilege, database,
+			))
+			continue
+		}
+
+		definition, known := privilegeCatalog[name]
+
+		// The listing holds the privileges the server actually defines (ALL is a keyword, not a privilege)
+		if serverPrivileges != nil && name != "ALL" {
+			if !serverPrivileges[name] {
+				diags.AddAttributeError(attributePath, "Invalid privilege", fmt.Sprintf(
+					"%q is not a privilege of the %s cluster (`SHOW PRIVILEGES`)%s", privilege, connection.ResourceArn, privilegeSuggestion(name),
+				))
+				continue
+			}
+
+			// Registered by a server component or plugin
+			if !known {
+				continue
+			}
+		}
+
+		if !known {
+			if dynamicPrivilegeNameRegex.MatchString(name) {
+				if capabilities := detectCapabilities(); capabilities == nil || capabilities.supports(capabilityDynamicPrivileges) {
+					diags.AddAttributeWarning(attributePath, "Unknown privilege", fmt.Sprintf(
+						"%q is not a known MySQL privilege%s. It may be a dynamic privilege registered by a server component, "+
+							"enable the provider `validate_on_plan` setting to check it against the server privileges",
+						privilege, privilegeSuggestion(name),
+					))
+					continue
+				}
+			}
+
+			diags.AddAttributeError(attributePath, "Invalid privilege", fmt.Sprintf(
+				"%q is not a MySQL privilege%s", privilege, privilegeSuggestion(name),
+			))
+			continue
+		}
+
+		// Only the privileges introduced or removed by a MySQL release depend on the server version
+		if definition.Since != (mysqlVersion{}) || definition.Until != (mysqlVersion{}) {
+			detectCapabilities()
+		}
+
+		if capabilities != nil && !capabilities.Version.atLeast(definition.Since) {
+			diags.AddAttributeError(attributePath, "Invalid privilege", fmt.Sprintf(
+				"%q requires MySQL >= %s, but the %s cluster runs %s", privilege, definition.Since, connection.ResourceArn, capabilities,
+			))
+			continue
+		}
+
+		if capabilities != nil && definition.Until != (mysqlVersion{}) && capabilities.Version.atLeast(definition.Until) {
+			diags.AddAttributeError(attributePath, "Invalid privilege", fmt.Sprintf(
+				"%q was removed in MySQL %s, and the %s cluster runs %s", privilege, definition.Until, connection.ResourceArn, capabilities,
+			))
+			continue
+		}
+
+		if definition.Scopes&scope == 0 {
+			diags.AddAttributeError(attributePath, "Invalid privilege", fmt.Sprintf(
+				"%q cannot be granted on `%s.*`, it only applies at the %s level", privilege, database, definition.Scopes,
+			))
+		}
+	}
+
+	return diags
+}
+
+// privilegeSuggestion returns the ` (did you mean "X"?)` hint of the catalog privileges closest to a misspelled
+// privilege name, empty when none is close enough.
+func privilegeSuggestion(name string) string {
+	var suggestions []string
+
+	for privilege := range privilegeCatalog {
+		if editDistance(name, privilege) <= 2 {
+			suggestions = append(suggestions, fmt.Sprintf("%q", privilege))
+		}
+	}
+
+	if len(suggestions) == 0 {
+		return ""
+	}
+
+	sort.Strings(suggestions)
+
+	return fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, " or "))
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestValidatePrivileges(t *testing.T) {
+	tests := []struct {
+		database        string
+		privilege       string
+		expectedError   bool
+		expectedWarning bool
+	}{
+		{database: "app", privilege: "SELECT"},
+		{database: "app", privilege: "all privileges"},
+		{database: "*", privilege: "RELOAD"},
+		{database: "app", privilege: "RELOAD", expectedError: true},
+		{database: "app", privilege: "SELCT", expectedError: true},
+		{database: "app", privilege: "SELECT (id)", expectedError: true},
+		// PROXY is granted on an account, not on a database
+		{database: "app", privilege: "PROXY", expectedError: true},
+		{database: "*", privilege: "PROXY", expectedError: true},
+		// Without a detected server version, unknown dynamic privileges may be registered by a server component
+		{database: "*", privilege: "CUSTOM_ADMIN", expectedWarning: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.privilege+" "+test.database, func(t *testing.T) {
+			d := &RdsDataProviderData{}
+
+			diags := d.validatePrivileges(context.Background(), databaseConnection{}, test.database, []string{test.privilege}, path.Root("privileges"))
+
+			if diags.HasError() != test.expectedError {
+				t.Errorf("validatePrivileges(%q, %q) = %v, expected an error: %t", test.database, test.privilege, diags, test.expectedError)
+			}
+
+			if hasWarning := diags.WarningsCount() > 0; hasWarning != test.expectedWarning {
+				t.Errorf("validatePrivileges(%q, %q) = %v, expected a warning: %t", test.database, test.privilege, diags, test.expectedWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// The private state key of the operation progress.
+const operationProgressKey = "operation_progress"
+
+// operationProgress records the completed steps of a multi-statement operation, so an operation failing halfway
+// resumes from the failed step on the next apply instead of repeating the completed ones.
+type operationProgress struct {
+	// Target fingerprints the planned values: the progress of another plan is discarded
+	Target string   `json:"target"`
+	Steps  []string `json:"steps"`
+}
+
+// privateState is implemented by the resource private state data (e.g. resp.Private).
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// operationTarget returns the fingerprint of the planned values of an operation.
+func operationTarget(values ...string) string {
+	data, _ := json.Marshal(values)
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// loadOperationProgress returns the progress of the operation recorded for target by a previous (failed) apply.
+func loadOperationProgress(ctx context.Context, private privateState, target string) (*operationProgress, diag.Diagnostics) {
+	progress := &operationProgress{Target: target}
+
+	data, diags := private.GetKey(ctx, operationProgressKey)
+	if diags.HasError() || len(data) == 0 {
+		return progress, diags
+	}
+
+	var recorded operationProgress
+
+	if err := json.Unmarshal(data, &recorded); err == nil && recorded.Target == target {
+		progress.Steps = recorded.Steps
+	}
+
+	return progress, diags
+}
+
+// done reports whether the step completed.
+func (p *operationProgress) done(step string) bool {
+	return slices.Contains(p.Steps, step)
+}
+
+// complete records the step completion.
+func (p *operationProgress) complete(step string) {
+	if !p.done(step) {
+		p.Steps = append(p.Steps, step)
+	}
+}
+
+// save records the progress into the private state, to resume the operation after a failure.
+func (p *operationProgress) save(ctx context.Context, private privateState) diag.Diagnostics {
+	data, err := json.Marshal(p)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Operation progress error", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, operationProgressKey, data)
+}
+
+// clear removes the progress from the private state, once the operation completed.
+func (p *operationProgress) clear(ctx context.Context, private privateState) diag.Diagnostics {
+	return private.SetKey(ctx, operationProgressKey, nil)
+}
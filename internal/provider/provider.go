@@ -3,9 +3,10 @@ package provider
 import (
 	"context"
 	"regexp"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -29,7 +30,44 @@ type RdsDataProvider struct {
 
 // RdsDataProviderModel describes the provider data model.
 type RdsDataProviderModel struct {
-	Region types.String `tfsdk:"region"`
+	Region              types.String `tfsdk:"region"`
+	AllowedAccountIds   types.Set    `tfsdk:"allowed_account_ids"`
+	ForbiddenAccountIds types.Set    `tfsdk:"forbidden_account_ids"`
+	ReadOnly            types.Bool   `tfsdk:"read_only"`
+	SqlPreview          types.Bool   `tfsdk:"sql_preview"`
+	ValidateOnPlan      types.Bool   `tfsdk:"validate_on_plan"`
+
+	Policy   *RdsDataProviderPolicyModel   `tfsdk:"policy"`
+	AuditLog *RdsDataProviderAuditLogModel `tfsdk:"audit_log"`
+}
+
+// RdsDataProviderData holds the AWS service clients shared with resources and data sources.
+type RdsDataProviderData struct {
+	// DefaultRegion is the region used for ARNs without a region
+	DefaultRegion string
+	// AllowedAccountIds and ForbiddenAccountIds restrict the AWS accounts of the managed database clusters
+	AllowedAccountIds   []string
+	ForbiddenAccountIds []string
+	// ReadOnly rejects every statement changing the databases
+	ReadOnly bool
+	// SqlPreview renders the statements of every resource change as plan warnings
+	SqlPreview bool
+	// ValidateOnPlan checks the database connections and privileges when planning resources
+	ValidateOnPlan bool
+	// Policy holds the guardrails evaluated when planning resources
+	Policy *guardrailPolicy
+	// AuditLog records every statement run, nil when disabled
+	AuditLog *auditLog
+	clients  *awsClientsCache
+	// principalUsernames caches the database usernames of the connection secrets
+	principalUsernames sync.Map
+	// connectionProbes caches the plan time checks of each database connection
+	connectionProbes sync.Map
+	// serverCapabilities caches the MySQL server version of each database cluster
+	serverCapabilities sync.Map
+	// callerAccountId caches the AWS account of the provider credentials, once resolved
+	callerAccountId    string
+	callerAccountMutex sync.Mutex
 }
 
 func (p *RdsDataProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -41,7 +79,7 @@ func (p *RdsDataProvider) Schema(ctx context.Context, req provider.SchemaRequest
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"region": schema.StringAttribute{
-				MarkdownDescription: "The RDS data service AWS region",
+				MarkdownDescription: "The default AWS region, used when it can't be derived from the resource ARNs",
 				Optional:            true,
 				Validators: []validator.String{
 					stringvalidator.RegexMatches(
@@ -50,6 +88,58 @@ func (p *RdsDataProvider) Schema(ctx context.Context, req provider.SchemaRequest
 					),
 				},
 			},
+			"allowed_account_ids": schema.SetAttribute{
+				MarkdownDescription: "The AWS account IDs the provider is allowed to manage: the provider credentials and the " +
+					"database cluster, secret (connection, password and managed secrets) and assumed role ARNs of every resource " +
+					"must belong to one of them. Without a provider `region`, the provider credentials are checked with the STS " +
+					"endpoint of the first cluster region. Conflicts with `forbidden_account_ids`",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ConflictsWith(path.MatchRoot("forbidden_account_ids")),
+					setvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^\d{12}$`), "must contain a valid AWS account ID"),
+					),
+				},
+			},
+			"forbidden_account_ids": schema.SetAttribute{
+				MarkdownDescription: "The AWS account IDs the provider must never manage: neither the provider credentials nor the " +
+					"database cluster, secret (connection, password and managed secrets) and assumed role ARNs of any resource " +
+					"may belong to one of them. Conflicts with `allowed_account_ids`",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ConflictsWith(path.MatchRoot("allowed_account_ids")),
+					setvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^\d{12}$`), "must contain a valid AWS account ID"),
+					),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider must never change the databases, e.g. in plan-only pipelines: data sources " +
+					"and resource reads run normally, but creating, updating or deleting resources fails before any statement " +
+					"is sent. Defaults to `false`",
+				Optional: true,
+			},
+			"sql_preview": schema.BoolAttribute{
+				MarkdownDescription: "Whether to render the SQL statements each `awsrdsdata_mysql_user` and `awsrdsdata_mysql_grant` " +
+					"resource will run on apply as plan warnings, with passwords redacted. Values only known on apply are shown " +
+					"as `(known after apply)`. Defaults to `false`",
+				Optional: true,
+			},
+			"validate_on_plan": schema.BoolAttribute{
+				MarkdownDescription: "Whether to check, when planning resources, that the database of each connection can be " +
+					"reached with its secret (`SELECT CURRENT_USER(), VERSION()`), that the secret account holds the privileges " +
+					"the planned changes need (`SHOW GRANTS FOR CURRENT_USER()`) and that the granted privileges are defined by " +
+					"the server (`SHOW PRIVILEGES`). The checks run once per cluster and secret. The MySQL version of each cluster is " +
+					"detected regardless of this setting, when planning attributes and privileges depending on it. Defaults to `false`",
+				Optional: true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"policy":    policyBlockSchema(),
+			"audit_log": auditLogBlockSchema(),
 		},
 	}
 }
@@ -75,6 +165,14 @@ func (p *RdsDataProvider) Configure(ctx context.Context, req provider.ConfigureR
 		)
 	}
 
+	if provider_config.AllowedAccountIds.IsUnknown() || provider_config.ForbiddenAccountIds.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown AWS account IDs.",
+			"The provider cannot check the AWS accounts because the allowed_account_ids or forbidden_account_ids attribute value is not known. "+
+				"Either apply the source of the value first, or set the attribute value statically in the configuration",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -91,17 +189,52 @@ func (p *RdsDataProvider) Configure(ctx context.Context, req provider.ConfigureR
 		)
 		return
 	}
-	// Finally, create the Amazon RDS Data service client to be used by resources
-	aws_rds_data_client := rdsdata.NewFromConfig(aws_client_cfg)
 
-	resp.DataSourceData = aws_rds_data_client
-	resp.ResourceData = aws_rds_data_client
+	// Then, set up the Amazon RDS, RDS Data and Secrets Manager service clients to be used by resources:
+	// they are built on first use for the region (and IAM role) of each database cluster
+	provider_data := &RdsDataProviderData{
+		DefaultRegion:  aws_client_cfg.Region,
+		ReadOnly:       provider_config.ReadOnly.ValueBool(),
+		SqlPreview:     provider_config.SqlPreview.ValueBool(),
+		ValidateOnPlan: provider_config.ValidateOnPlan.ValueBool(),
+		clients:        newAwsClientsCache(aws_client_cfg),
+	}
+
+	resp.Diagnostics.Append(provider_config.AllowedAccountIds.ElementsAs(ctx, &provider_data.AllowedAccountIds, false)...)
+	resp.Diagnostics.Append(provider_config.ForbiddenAccountIds.ElementsAs(ctx, &provider_data.ForbiddenAccountIds, false)...)
+
+	policy, diags := newGuardrailPolicy(ctx, provider_config.Policy)
+	resp.Diagnostics.Append(diags...)
+	provider_data.Policy = policy
+
+	audit_log, err := newAuditLog(provider_config.AuditLog)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("audit_log"), "Audit Log Error", err.Error())
+	}
+	provider_data.AuditLog = audit_log
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Finally, make sure the provider credentials belong to an allowed AWS account. Without a provider region, the STS
+	// endpoint of the first database cluster (or secret) region is used instead, on the first request sent
+	if provider_data.checkAccounts() && aws_client_cfg.Region != "" {
+		if err := provider_data.checkCallerAccount(ctx, aws_client_cfg.Region); err != nil {
+			resp.Diagnostics.AddError("AWS Account Check Error", err.Error())
+			return
+		}
+	}
+
+	resp.DataSourceData = provider_data
+	resp.ResourceData = provider_data
 }
 
 func (p *RdsDataProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewMysqlUserResource,
 		NewMysqlGrantResource,
+		NewMysqlUserMultiHostResource,
 	}
 }
 
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// rdsClusterEndpoint holds the connection details of an RDS cluster.
+type rdsClusterEndpoint struct {
+	ClusterIdentifier string
+	Host              string
+	Port              int32
+}
+
+// describeClusterEndpoint resolves the writer endpoint and port of the RDS cluster identified by clusterArn.
+func describeClusterEndpoint(ctx context.Context, client *rds.Client, clusterArn string) (rdsClusterEndpoint, error) {
+	describeClustersOpts := rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(clusterArn),
+	}
+
+	clusters, err := client.DescribeDBClusters(ctx, &describeClustersOpts)
+	if err != nil {
+		return rdsClusterEndpoint{}, err
+	}
+
+	if len(clusters.DBClusters) == 0 {
+		return rdsClusterEndpoint{}, fmt.Errorf("RDS cluster %s not found", clusterArn)
+	}
+
+	cluster := clusters.DBClusters[0]
+
+	return rdsClusterEndpoint{
+		ClusterIdentifier: aws.ToString(cluster.DBClusterIdentifier),
+		Host:              aws.ToString(cluster.Endpoint),
+		Port:              aws.ToInt32(cluster.Port),
+	}, nil
+}
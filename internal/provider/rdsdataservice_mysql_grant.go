@@ -3,18 +3,16 @@ package provider
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	"github.com/dcarbone/terraform-plugin-framework-utils/v3/conv"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -24,6 +22,9 @@ import (
 var (
 	_ resource.Resource                = &MysqlGrantResource{}
 	_ resource.ResourceWithImportState = &MysqlGrantResource{}
+	_ resource.ResourceWithModifyPlan  = &MysqlGrantResource{}
+
+	_ resource.ResourceWithConfigValidators = &MysqlGrantResource{}
 )
 
 func NewMysqlGrantResource() resource.Resource {
@@ -32,17 +33,28 @@ func NewMysqlGrantResource() resource.Resource {
 
 // MysqlGrantResource defines the resource implementation.
 type MysqlGrantResource struct {
-	client *rdsdata.Client
+	providerData *RdsDataProviderData
 }
 
 // MysqlGrantResourceModel describes the resource data model.
 type MysqlGrantResourceModel struct {
-	User                types.String `tfsdk:"user"`
-	Host                types.String `tfsdk:"host"`
-	Database            types.String `tfsdk:"database"`
-	Privileges          types.List   `tfsdk:"privileges"`
-	DatabaseResourceArn types.String `tfsdk:"database_resource_arn"`
-	DatabaseSecretArn   types.String `tfsdk:"database_secret_arn"`
+	User                types.String             `tfsdk:"user"`
+	Host                HostPatternValue         `tfsdk:"host"`
+	Database            types.String             `tfsdk:"database"`
+	Privileges          types.List               `tfsdk:"privileges"`
+	DatabaseResourceArn ArnValue                 `tfsdk:"database_resource_arn"`
+	DatabaseSecretArn   ArnValue                 `tfsdk:"database_secret_arn"`
+	Connection          *DatabaseConnectionModel `tfsdk:"database_connection"`
+}
+
+// connection returns the database connection of the resource.
+func (m *MysqlGrantResourceModel) connection() databaseConnection {
+	return newDatabaseConnection(m.DatabaseResourceArn, m.DatabaseSecretArn, m.Connection)
+}
+
+// auditId identifies the resource in the audit log.
+func (m *MysqlGrantResourceModel) auditId() string {
+	return fmt.Sprintf("'%s'@'%s' ON %s.*", m.User.ValueString(), m.Host.ValueString(), m.Database.ValueString())
 }
 
 func (r *MysqlGrantResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -62,18 +74,13 @@ func (r *MysqlGrantResource) Schema(ctx context.Context, req resource.SchemaRequ
 					// user value cannot be empty
 					stringvalidator.LengthAtLeast(1),
 					// protect against destroying system accounts
-					stringvalidator.NoneOf([]string{"sys"}...),
+					stringvalidator.NoneOf(builtinProtectedAccounts...),
 				},
 			},
 			"host": schema.StringAttribute{
 				MarkdownDescription: "The host field associated with the MySQL user",
 				Required:            true,
-				Validators: []validator.String{
-					stringvalidator.RegexMatches(
-						regexp.MustCompile(`^(([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]*[a-zA-Z0-9])\.)*([A-Za-z0-9]|[A-Za-z0-9][A-Za-z0-9\-]*[A-Za-z0-9]|%)$`),
-						"must contain a valid hostname value",
-					),
-				},
+				CustomType:          HostPatternType{},
 			},
 			"database": schema.StringAttribute{
 				MarkdownDescription: "The MySQL database to grant privileges for",
@@ -81,13 +88,14 @@ func (r *MysqlGrantResource) Schema(ctx context.Context, req resource.SchemaRequ
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
 					// protect against destroying system databases
-					stringvalidator.NoneOf([]string{"rdsadmin", "mysql.sys"}...),
+					stringvalidator.NoneOf(builtinProtectedDatabases...),
 				},
 			},
 			"privileges": schema.ListAttribute{
-				MarkdownDescription: "The MySQL user privileges to grant",
-				Required:            true,
-				ElementType:         types.StringType,
+				MarkdownDescription: "The MySQL user privileges to grant, checked when planning against the privileges (and the levels " +
+					"they apply to) of MySQL, and of the cluster MySQL version for the privileges introduced or removed by a MySQL release",
+				Required:    true,
+				ElementType: types.StringType,
 				Validators: []validator.List{
 					// at least one privilege must be defined
 					listvalidator.SizeAtLeast(1),
@@ -96,53 +104,147 @@ func (r *MysqlGrantResource) Schema(ctx context.Context, req resource.SchemaRequ
 				},
 			},
 			"database_resource_arn": schema.StringAttribute{
-				MarkdownDescription: "The RDS database resource ARN to run SQL queries against",
-				Required:            true,
-				Validators: []validator.String{
-					stringvalidator.RegexMatches(
-						regexp.MustCompile(`^arn:aws:rds:.*\w-.*\w-.*\d:.*\d:cluster:.*\w|[-,_]$`),
-						"must contain a valid ARN resource value",
-					),
-				},
+				MarkdownDescription: "The RDS database cluster ARN to run SQL queries against, in any AWS partition. " +
+					"The SQL queries are sent to the cluster region. Required unless the `database_connection` block is set",
+				Optional:   true,
+				CustomType: ArnType{Kind: arnKindRdsCluster},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceIfConnectionChanged(),
 				},
 			},
 			"database_secret_arn": schema.StringAttribute{
-				MarkdownDescription: "The RDS database secret ARN to use for authentication",
-				Required:            true,
-				Validators: []validator.String{
-					stringvalidator.RegexMatches(
-						regexp.MustCompile(`^arn:aws:secretsmanager:.*\w-.*\w-.*\d:.*\d:secret:.*\w|[-,_]$`),
-						"must contain a valid ARN resource value",
-					),
-				},
+				MarkdownDescription: "The RDS database secret ARN to use for authentication. Required unless the `database_connection` block is set",
+				Optional:            true,
+				CustomType:          ArnType{Kind: arnKindSecretsManagerSecret},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceIfConnectionChanged(),
 				},
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"database_connection": connectionBlockSchema(),
+		},
 	}
 }
 
+func (r *MysqlGrantResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return connectionConfigValidators()
+}
+
 func (r *MysqlGrantResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*rdsdata.Client)
+	providerData, ok := req.ProviderData.(*RdsDataProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *rdsdata.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *RdsDataProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = providerData
+}
+
+func (r *MysqlGrantResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.providerData == nil {
+		return
+	}
+
+	// Only protect the provider database user on resource destruction
+	if req.Plan.Raw.IsNull() {
+		var state MysqlGrantResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, state.connection(), state.User.ValueString(), path.Root("user"))...)
+		}
+
+		if r.providerData.SqlPreview && !resp.Diagnostics.HasError() {
+			addSQLPreview(&resp.Diagnostics, []string{
+				revokePrivilegesStatement(
+					conv.StringListToStrings(state.Privileges), state.Database.ValueString(), state.User.ValueString(), state.Host.ValueString(),
+					r.providerData.supports(ctx, state.connection(), capabilityRevokeIfExists),
+				),
+			})
+		}
+
+		return
+	}
+
+	var plan, state MysqlGrantResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw) {
+		resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, plan.connection(), plan.User.ValueString(), path.Root("user"))...)
+	}
+
+	// Moving the grant away from the provider database user revokes its privileges
+	if !req.State.Raw.IsNull() && (!plan.User.Equal(state.User) || !plan.Host.Equal(state.Host)) {
+		resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, state.connection(), state.User.ValueString(), path.Root("user"))...)
+	}
+
+	// ======================= Provider policy =======================
+
+	if !plan.User.IsUnknown() && !plan.Host.IsUnknown() {
+		if err := r.providerData.Policy.checkAccount(plan.User.ValueString(), plan.Host.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("user"), "Provider policy violation", err.Error())
+		}
+	}
+
+	if !plan.Database.IsUnknown() && !plan.Privileges.IsUnknown() {
+		if err := r.providerData.Policy.checkGrant(plan.Database.ValueString(), conv.StringListToStrings(plan.Privileges)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("privileges"), "Provider policy violation", err.Error())
+		}
+	}
+
+	// ======================= Privileges =======================
+
+	if !resp.Diagnostics.HasError() && !plan.Database.IsUnknown() && !plan.Privileges.IsUnknown() &&
+		(req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw)) {
+		resp.Diagnostics.Append(r.providerData.validatePrivileges(
+			ctx,
+			plan.connection(),
+			plan.Database.ValueString(),
+			conv.StringListToStrings(plan.Privileges),
+			path.Root("privileges"),
+		)...)
+	}
+
+	// ======================= Plan validation =======================
+
+	if !resp.Diagnostics.HasError() && !plan.Database.IsUnknown() && !plan.Privileges.IsUnknown() &&
+		(req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw)) {
+		resp.Diagnostics.Append(r.providerData.validateOnPlan(
+			ctx,
+			plan.connection(),
+			grantRequiredPrivileges(plan.Database.ValueString(), conv.StringListToStrings(plan.Privileges)),
+		)...)
+	}
+
+	// ======================= SQL preview =======================
+
+	if r.providerData.SqlPreview && !resp.Diagnostics.HasError() && (req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw)) {
+		revokeIfExists := r.providerData.supports(ctx, plan.connection(), capabilityRevokeIfExists)
+
+		addSQLPreview(&resp.Diagnostics, plannedGrantStatements(plan, state, req.State.Raw.IsNull(), len(resp.RequiresReplace) > 0, revokeIfExists))
+	}
 }
 
 func (r *MysqlGrantResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -157,24 +259,23 @@ func (r *MysqlGrantResource) Create(ctx context.Context, req resource.CreateRequ
 
 	// ======================= Resource CREATE Logic =======================
 
-	grantUserPrivilegesSqlQuery := fmt.Sprintf(
-		"GRANT %s ON %s.* TO '%s'@'%s'",
-		strings.Join(conv.StringListToStrings(plan.Privileges), ","),
-		plan.Database.ValueString(),
-		plan.User.ValueString(),
-		plan.Host.ValueString(),
-	)
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_grant", plan.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource CREATE operation error", err)
+		return
+	}
+
+	grantUserPrivilegesSqlQuery := grantPrivilegesStatement(conv.StringListToStrings(plan.Privileges), plan.Database.ValueString(), plan.User.ValueString(), plan.Host.ValueString())
 
 	grantUserPrivilegesStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(plan.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(plan.DatabaseSecretArn.ValueString()),
-		Sql:         &grantUserPrivilegesSqlQuery,
+		Sql: &grantUserPrivilegesSqlQuery,
 	}
 
-	_, grantSqlQueryErr := r.client.ExecuteStatement(ctx, &grantUserPrivilegesStatementOpts)
+	_, grantSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &grantUserPrivilegesStatementOpts)
 
 	if grantSqlQueryErr != nil {
-		resp.Diagnostics.AddError("Resource CREATE operation error", grantSqlQueryErr.Error())
+		addOperationError(&resp.Diagnostics, "Resource CREATE operation error", grantSqlQueryErr)
 		return
 	}
 
@@ -196,6 +297,8 @@ func (r *MysqlGrantResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	// ======================= Resource READ Logic =======================
 
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_grant", state.auditId())
+
 	userGrantsSqlQuery := fmt.Sprintf(
 		"SHOW GRANTS FOR '%s'@'%s'",
 		state.User.ValueString(),
@@ -203,25 +306,19 @@ func (r *MysqlGrantResource) Read(ctx context.Context, req resource.ReadRequest,
 	)
 
 	userGrantsQueryStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(state.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(state.DatabaseSecretArn.ValueString()),
-		Sql:         &userGrantsSqlQuery,
+		Sql: &userGrantsSqlQuery,
 	}
 
-	userGrantsSqlQueryResult, userGrantsSqlQueryErr := r.client.ExecuteStatement(ctx, &userGrantsQueryStatementOpts)
+	userGrantsSqlQueryResult, userGrantsSqlQueryErr := r.providerData.QueryStatement(ctx, state.connection(), &userGrantsQueryStatementOpts)
 
-	userGrantsNotDefinedErrMsg := fmt.Sprintf(
-		"There is no such grant defined for user '%s' on host '%s'",
-		state.User.ValueString(),
-		state.Host.ValueString(),
-	)
-	if userGrantsSqlQueryErr != nil && !strings.Contains(userGrantsSqlQueryErr.Error(), userGrantsNotDefinedErrMsg) {
-		resp.Diagnostics.AddError("Resource READ operation error", userGrantsSqlQueryErr.Error())
+	if userGrantsSqlQueryErr != nil && !isMysqlError(userGrantsSqlQueryErr, mysqlErrorNonexistingGrant) {
+		addOperationError(&resp.Diagnostics, "Resource READ operation error", userGrantsSqlQueryErr)
 		return
 	}
 
-	// The `GRANT USAGE` privilege is always present so at least one record is returned
-	if userGrantsSqlQueryResult.Records != nil && len(userGrantsSqlQueryResult.Records) == 1 {
+	// The `GRANT USAGE` privilege is always present so at least one record is returned,
+	// unless the account itself is gone (e.g. renamed or dropped outside terraform)
+	if userGrantsSqlQueryErr != nil || (userGrantsSqlQueryResult.Records != nil && len(userGrantsSqlQueryResult.Records) == 1) {
 		tflog.Trace(ctx, "MySQL server returned no user grant records")
 		// Force resource recreation if GRANTS deleted outside terraform
 		state.Privileges = types.ListNull(types.StringType)
@@ -243,55 +340,63 @@ func (r *MysqlGrantResource) Update(ctx context.Context, req resource.UpdateRequ
 
 	// ======================= Resource UPDATE Logic =======================
 
-	// Revoke all privileges first
-	revokeUserPrivilegesSqlQuery := fmt.Sprintf(
-		// Use IF NOT EXISTS condition (only supported by MySQL version >= 8.0)
-		"REVOKE ALL PRIVILEGES ON %s.* FROM '%s'@'%s'",
-		plan.Database.ValueString(),
-		plan.User.ValueString(),
-		plan.Host.ValueString(),
-	)
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_grant", plan.auditId())
 
-	revokeUserPrivilegesStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(plan.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(plan.DatabaseSecretArn.ValueString()),
-		Sql:         &revokeUserPrivilegesSqlQuery,
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource UPDATE operation error", err)
+		return
 	}
 
-	_, revokeSqlQueryErr := r.client.ExecuteStatement(ctx, &revokeUserPrivilegesStatementOpts)
+	// Resume an update which failed halfway (the privileges are already revoked)
+	progress, progressDiags := loadOperationProgress(ctx, resp.Private, operationTarget(
+		append([]string{plan.Database.ValueString(), plan.User.ValueString(), plan.Host.ValueString()}, conv.StringListToStrings(plan.Privileges)...)...,
+	))
+	resp.Diagnostics.Append(progressDiags...)
 
-	userGrantsNotDefinedErrMsg := fmt.Sprintf(
-		"There is no such grant defined for user '%s' on host '%s'",
-		plan.User.ValueString(),
-		plan.Host.ValueString(),
-	)
-	if revokeSqlQueryErr != nil && !strings.Contains(revokeSqlQueryErr.Error(), userGrantsNotDefinedErrMsg) {
-		resp.Diagnostics.AddError("Resource UPDATE operation error", revokeSqlQueryErr.Error())
-		return
+	// Revoke all privileges first
+	if !progress.done("revoke") {
+		revokeUserPrivilegesSqlQuery := revokePrivilegesStatement(
+			[]string{"ALL PRIVILEGES"}, plan.Database.ValueString(), plan.User.ValueString(), plan.Host.ValueString(),
+			r.providerData.supports(ctx, plan.connection(), capabilityRevokeIfExists),
+		)
+
+		revokeUserPrivilegesStatementOpts := rdsdata.ExecuteStatementInput{
+			Sql: &revokeUserPrivilegesSqlQuery,
+		}
+
+		_, revokeSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &revokeUserPrivilegesStatementOpts)
+
+		if revokeSqlQueryErr != nil && !isMysqlError(revokeSqlQueryErr, mysqlErrorNonexistingGrant) {
+			addOperationError(&resp.Diagnostics, "Resource UPDATE operation error", revokeSqlQueryErr)
+			return
+		}
+
+		progress.complete("revoke")
 	}
 
 	// Grant new privileges
-	grantUserPrivilegesSqlQuery := fmt.Sprintf(
-		"GRANT %s ON %s.* TO '%s'@'%s'",
-		strings.Join(conv.StringListToStrings(plan.Privileges), ","),
-		plan.Database.ValueString(),
-		plan.User.ValueString(),
-		plan.Host.ValueString(),
-	)
+	grantUserPrivilegesSqlQuery := grantPrivilegesStatement(conv.StringListToStrings(plan.Privileges), plan.Database.ValueString(), plan.User.ValueString(), plan.Host.ValueString())
 
 	grantUserPrivilegesStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(plan.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(plan.DatabaseSecretArn.ValueString()),
-		Sql:         &grantUserPrivilegesSqlQuery,
+		Sql: &grantUserPrivilegesSqlQuery,
 	}
 
-	_, grantSqlQueryErr := r.client.ExecuteStatement(ctx, &grantUserPrivilegesStatementOpts)
+	_, grantSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &grantUserPrivilegesStatementOpts)
 
 	if grantSqlQueryErr != nil {
-		resp.Diagnostics.AddError("Resource UPDATE operation error", grantSqlQueryErr.Error())
+		addOperationError(&resp.Diagnostics, "Resource UPDATE operation error", grantSqlQueryErr)
+
+		// The account holds no privilege on the database anymore, the next apply grants them
+		partial := plan
+		partial.Privileges = types.ListNull(types.StringType)
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &partial)...)
+		resp.Diagnostics.Append(progress.save(ctx, resp.Private)...)
 		return
 	}
 
+	resp.Diagnostics.Append(progress.clear(ctx, resp.Private)...)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -308,29 +413,26 @@ func (r *MysqlGrantResource) Delete(ctx context.Context, req resource.DeleteRequ
 
 	// ======================= Resource DELETE Logic =======================
 
-	revokeUserPrivilegesSqlQuery := fmt.Sprintf(
-		"REVOKE %s ON %s.* FROM '%s'@'%s'",
-		strings.Join(conv.StringListToStrings(state.Privileges), ","),
-		state.Database.ValueString(),
-		state.User.ValueString(),
-		state.Host.ValueString(),
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_grant", state.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource DELETE operation error", err)
+		return
+	}
+
+	revokeUserPrivilegesSqlQuery := revokePrivilegesStatement(
+		conv.StringListToStrings(state.Privileges), state.Database.ValueString(), state.User.ValueString(), state.Host.ValueString(),
+		r.providerData.supports(ctx, state.connection(), capabilityRevokeIfExists),
 	)
 
 	deleteUserStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(state.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(state.DatabaseSecretArn.ValueString()),
-		Sql:         &revokeUserPrivilegesSqlQuery,
+		Sql: &revokeUserPrivilegesSqlQuery,
 	}
 
-	_, revokeUserPrivilegesSqlQueryErr := r.client.ExecuteStatement(ctx, &deleteUserStatementOpts)
+	_, revokeUserPrivilegesSqlQueryErr := r.providerData.ExecuteStatement(ctx, state.connection(), &deleteUserStatementOpts)
 
-	userGrantsNotDefinedErrMsg := fmt.Sprintf(
-		"There is no such grant defined for user '%s' on host '%s'",
-		state.User.ValueString(),
-		state.Host.ValueString(),
-	)
-	if revokeUserPrivilegesSqlQueryErr != nil && !strings.Contains(revokeUserPrivilegesSqlQueryErr.Error(), userGrantsNotDefinedErrMsg) {
-		resp.Diagnostics.AddError("Resource DELETE operation error", revokeUserPrivilegesSqlQueryErr.Error())
+	if revokeUserPrivilegesSqlQueryErr != nil && !isMysqlError(revokeUserPrivilegesSqlQueryErr, mysqlErrorNonexistingGrant) {
+		addOperationError(&resp.Diagnostics, "Resource DELETE operation error", revokeUserPrivilegesSqlQueryErr)
 		return
 	}
 }
@@ -339,3 +441,39 @@ func (r *MysqlGrantResource) ImportState(ctx context.Context, req resource.Impor
 	// TO DO
 	//resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// plannedGrantStatements returns the statements Create (when create is set) or Update will run to apply the plan,
+// the privileges of a replaced grant are revoked first.
+func plannedGrantStatements(plan MysqlGrantResourceModel, state MysqlGrantResourceModel, create bool, replace bool, revokeIfExists bool) []string {
+	database, user, host := previewString(plan.Database), previewString(plan.User), previewString(plan.Host)
+	grant := grantPrivilegesStatement(previewStrings(plan.Privileges), database, user, host)
+
+	switch {
+	case create:
+		return []string{grant}
+	case replace:
+		return []string{
+			revokePrivilegesStatement(
+				conv.StringListToStrings(state.Privileges), state.Database.ValueString(), state.User.ValueString(), state.Host.ValueString(), revokeIfExists,
+			),
+			grant,
+		}
+	default:
+		return []string{revokePrivilegesStatement([]string{"ALL PRIVILEGES"}, database, user, host, revokeIfExists), grant}
+	}
+}
+
+// grantPrivilegesStatement returns the statement granting the privileges on every table of the database.
+func grantPrivilegesStatement(privileges []string, database string, user string, host string) string {
+	return fmt.Sprintf("GRANT %s ON %s.* TO '%s'@'%s'", strings.Join(privileges, ","), database, user, host)
+}
+
+// revokePrivilegesStatement returns the statement revoking the privileges on every table of the database. With
+// ifExists (MySQL >= 8.0.30), revoking privileges the account does not hold, or from a missing account, is not an error.
+func revokePrivilegesStatement(privileges []string, database string, user string, host string, ifExists bool) string {
+	if ifExists {
+		return fmt.Sprintf("REVOKE IF EXISTS %s ON %s.* FROM '%s'@'%s' IGNORE UNKNOWN USER", strings.Join(privileges, ","), database, user, host)
+	}
+
+	return fmt.Sprintf("REVOKE %s ON %s.* FROM '%s'@'%s'", strings.Join(privileges, ","), database, user, host)
+}
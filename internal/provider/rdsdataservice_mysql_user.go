@@ -2,16 +2,25 @@ package provider
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -22,6 +31,9 @@ import (
 var (
 	_ resource.Resource                = &MysqlUserResource{}
 	_ resource.ResourceWithImportState = &MysqlUserResource{}
+	_ resource.ResourceWithModifyPlan  = &MysqlUserResource{}
+
+	_ resource.ResourceWithConfigValidators = &MysqlUserResource{}
 )
 
 func NewMysqlUserResource() resource.Resource {
@@ -30,18 +42,86 @@ func NewMysqlUserResource() resource.Resource {
 
 // MysqlUserResource defines the resource implementation.
 type MysqlUserResource struct {
-	client *rdsdata.Client
+	providerData *RdsDataProviderData
 }
 
 // MysqlUserResourceModel describes the resource data model.
 type MysqlUserResourceModel struct {
-	User                types.String `tfsdk:"user"`
-	Password            types.String `tfsdk:"password"`
-	Host                types.String `tfsdk:"host"`
-	DatabaseResourceArn types.String `tfsdk:"database_resource_arn"`
-	DatabaseSecretArn   types.String `tfsdk:"database_secret_arn"`
+	User                types.String             `tfsdk:"user"`
+	Password            types.String             `tfsdk:"password"`
+	PasswordWo          types.String             `tfsdk:"password_wo"`
+	PasswordVersion     types.Int64              `tfsdk:"password_version"`
+	PasswordHash        types.String             `tfsdk:"password_hash"`
+	AuthStringHash      types.String             `tfsdk:"auth_string_hash"`
+	AuthPlugin          types.String             `tfsdk:"auth_plugin"`
+	PasswordLength      types.Int64              `tfsdk:"password_length"`
+	PasswordCharset     types.String             `tfsdk:"password_charset"`
+	PasswordKeepers     types.Map                `tfsdk:"password_keepers"`
+	GeneratedPassword   types.String             `tfsdk:"generated_password"`
+	PasswordInSync      types.Bool               `tfsdk:"password_in_sync"`
+	RenameStrategy      types.String             `tfsdk:"rename_strategy"`
+	Host                HostPatternValue         `tfsdk:"host"`
+	DatabaseResourceArn ArnValue                 `tfsdk:"database_resource_arn"`
+	DatabaseSecretArn   ArnValue                 `tfsdk:"database_secret_arn"`
+	Connection          *DatabaseConnectionModel `tfsdk:"database_connection"`
+	AccountLocked       types.Bool               `tfsdk:"account_locked"`
+	DeletionMode        types.String             `tfsdk:"deletion_mode"`
+	PasswordRotation    types.String             `tfsdk:"password_rotation_mode"`
+	DiscardOldAfter     types.String             `tfsdk:"discard_old_password_after"`
+	RotationPhase       types.String             `tfsdk:"password_rotation_phase"`
+	PasswordRotatedAt   types.String             `tfsdk:"password_rotated_at"`
+
+	PasswordSecretArn          ArnValue     `tfsdk:"password_secret_arn"`
+	PasswordSecretJsonKey      types.String `tfsdk:"password_secret_json_key"`
+	PasswordSecretVersionStage types.String `tfsdk:"password_secret_version_stage"`
+	PasswordSecretVersionId    types.String `tfsdk:"password_secret_version_id"`
+
+	ManagedSecret *MysqlUserManagedSecretModel `tfsdk:"managed_secret"`
+}
+
+// connection returns the database connection of the resource.
+func (m *MysqlUserResourceModel) connection() databaseConnection {
+	return newDatabaseConnection(m.DatabaseResourceArn, m.DatabaseSecretArn, m.Connection)
+}
+
+// auditId identifies the resource in the audit log.
+func (m *MysqlUserResourceModel) auditId() string {
+	return fmt.Sprintf("'%s'@'%s'", m.User.ValueString(), m.Host.ValueString())
 }
 
+// Supported values for the `deletion_mode` attribute.
+const (
+	mysqlUserDeletionModeDrop    = "drop"
+	mysqlUserDeletionModeLock    = "lock"
+	mysqlUserDeletionModeAbandon = "abandon"
+)
+
+// Supported values for the `auth_plugin` attribute.
+const (
+	mysqlAuthPluginNativePassword      = "mysql_native_password"
+	mysqlAuthPluginCachingSha2Password = "caching_sha2_password"
+)
+
+// Supported values for the `rename_strategy` attribute.
+const (
+	mysqlUserRenameStrategyReplace = "replace"
+	mysqlUserRenameStrategyRename  = "rename"
+)
+
+// Supported values for the `password_rotation_mode` attribute.
+const (
+	mysqlUserPasswordRotationReplace = "replace"
+	mysqlUserPasswordRotationRetain  = "retain_current_password"
+)
+
+// Values reported by the `password_rotation_phase` attribute.
+const (
+	// only the current password is accepted by the server
+	mysqlUserPasswordPhaseSingle = "single_password"
+	// both the current and the previous (retained) passwords are accepted by the server
+	mysqlUserPasswordPhaseDual = "dual_password"
+)
+
 func (r *MysqlUserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_mysql_user"
 }
@@ -59,82 +139,525 @@ func (r *MysqlUserResource) Schema(ctx context.Context, req resource.SchemaReque
 					// user value cannot be empty
 					stringvalidator.LengthAtLeast(1),
 					// protect against destroying system accounts
-					stringvalidator.NoneOf([]string{"rdsadmin", "mysql.sys"}...),
+					stringvalidator.NoneOf(builtinProtectedAccounts...),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessRenamed(),
 				},
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "The MySQL password to set for the user (must be at least 16 characters long)",
-				Required:            true,
-				Sensitive:           true,
+				MarkdownDescription: "The MySQL password to set for the user (must be at least 16 characters long). " +
+					"Conflicts with `password_wo`, `password_secret_arn` and `auth_string_hash`",
+				Optional:  true,
+				Sensitive: true,
 				Validators: []validator.String{
 					// password must be at least 16 characters long
-					stringvalidator.LengthAtLeast(16),
+					stringvalidator.LengthAtLeast(minPasswordLength),
 				},
 			},
-			"host": schema.StringAttribute{
-				MarkdownDescription: "The MySQL user host value",
-				Required:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+			"password_wo": schema.StringAttribute{
+				MarkdownDescription: "The MySQL password to set for the user (must be at least 16 characters long), as a write-only " +
+					"attribute: it is never persisted in the Terraform plan or state (requires Terraform >= 1.11). " +
+					"Changes are detected through `password_hash`, or forced by bumping `password_version`. " +
+					"Conflicts with `password`, `password_secret_arn` and `auth_string_hash`",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Validators: []validator.String{
+					// password must be at least 16 characters long
+					stringvalidator.LengthAtLeast(minPasswordLength),
+				},
+			},
+			"password_version": schema.Int64Attribute{
+				MarkdownDescription: "Arbitrary version number of the `password_wo` password, changing it triggers a password update",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("password_wo")),
+				},
+			},
+			"password_hash": schema.StringAttribute{
+				MarkdownDescription: "A salted SHA-256 fingerprint (`sha256:<salt>:<digest>`) of the `password_wo` password, " +
+					"used to detect password changes without storing the password in the Terraform state",
+				Computed: true,
+			},
+			"password_length": schema.Int64Attribute{
+				MarkdownDescription: "The length of the password generated when no password is configured " +
+					"(`password`, `password_wo`, `password_secret_arn` or `auth_string_hash`). " +
+					"The server `validate_password` minimum length takes precedence when higher. " +
+					"Changes apply to the next password generated (see `password_keepers`). Defaults to `32`",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(32),
+				Validators: []validator.Int64{
+					int64validator.Between(16, 256),
 				},
+			},
+			"password_charset": schema.StringAttribute{
+				MarkdownDescription: "The characters generated passwords are built from (quotes and backslashes are not allowed). " +
+					"Changes apply to the next password generated (see `password_keepers`). " +
+					"Defaults to letters, digits and the `!#%*+-.:=?@^_~` special characters",
+				Optional: true,
 				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
 					stringvalidator.RegexMatches(
-						regexp.MustCompile(`^(([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]*[a-zA-Z0-9])\.)*([A-Za-z0-9]|[A-Za-z0-9][A-Za-z0-9\-]*[A-Za-z0-9]|%)$`),
-						"must contain a valid hostname value",
+						regexp.MustCompile(`^[^'"\\\s]+$`),
+						"must not contain quotes, backslashes or whitespaces",
 					),
 				},
 			},
-			"database_resource_arn": schema.StringAttribute{
-				MarkdownDescription: "The RDS database resource ARN to run SQL queries against",
-				Required:            true,
+			"password_keepers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary map of values that, when changed, trigger a new generated password",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"generated_password": schema.StringAttribute{
+				MarkdownDescription: "The password generated for the user when no password is configured",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"password_in_sync": schema.BoolAttribute{
+				MarkdownDescription: "Whether the server `mysql.user.authentication_string` matches the configured password. " +
+					"It turns `false` when the password is changed outside Terraform, so the next apply restores it " +
+					"(only checked for `password`, `password_secret_arn` and generated passwords of " +
+					"`mysql_native_password` and `caching_sha2_password` users)",
+				Computed: true,
+			},
+			"auth_string_hash": schema.StringAttribute{
+				MarkdownDescription: "A pre-hashed authentication string for the `auth_plugin` authentication plugin " +
+					"(`IDENTIFIED WITH <auth_plugin> AS '<auth_string_hash>'`), as stored in `mysql.user.authentication_string`. " +
+					"Binary hashes (e.g. `caching_sha2_password`) can be given as hex literals (`0x...`). " +
+					"Conflicts with `password`, `password_wo` and `password_secret_arn`",
+				Optional:  true,
+				Sensitive: true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(
-						regexp.MustCompile(`^arn:aws:rds:.*\w-.*\w-.*\d:.*\d:cluster:.*\w|[-,_]$`),
-						"must contain a valid ARN resource value",
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("auth_plugin")),
+				},
+			},
+			"auth_plugin": schema.StringAttribute{
+				MarkdownDescription: "The authentication plugin the `auth_string_hash` hash was produced for: " +
+					"`mysql_native_password` or `caching_sha2_password`",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						mysqlAuthPluginNativePassword,
+						mysqlAuthPluginCachingSha2Password,
 					),
+					stringvalidator.AlsoRequires(path.MatchRoot("auth_string_hash")),
+				},
+			},
+			"password_secret_arn": schema.StringAttribute{
+				MarkdownDescription: "The ARN of an AWS Secrets Manager secret holding the MySQL password to set for the user. " +
+					"The password is fetched at apply time and never stored in the Terraform state. " +
+					"Conflicts with `password`, `password_wo` and `auth_string_hash`",
+				Optional:   true,
+				CustomType: ArnType{Kind: arnKindSecretsManagerSecret},
+			},
+			"password_secret_json_key": schema.StringAttribute{
+				MarkdownDescription: "The key holding the password when the `password_secret_arn` secret is a JSON document " +
+					"(e.g. `password`). When not set, the whole secret string is used as the password",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("password_secret_arn")),
 				},
+			},
+			"password_secret_version_stage": schema.StringAttribute{
+				MarkdownDescription: "The staging label of the `password_secret_arn` secret version to use. Defaults to `AWSCURRENT`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("password_secret_arn")),
+				},
+			},
+			"password_secret_version_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the `password_secret_arn` secret version the current password was read from",
+				Computed:            true,
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "The MySQL user host value: `%`, a hostname or hostname pattern, an IPv4 or IPv6 address or pattern, " +
+					"an IPv4 address with a netmask (`10.0.0.0/255.255.0.0`) or an IPv4 CIDR block (`10.0.0.0/16`, MySQL >= 8.0.23)",
+				Required:   true,
+				CustomType: HostPatternType{},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessRenamed(),
+				},
+			},
+			"database_resource_arn": schema.StringAttribute{
+				MarkdownDescription: "The RDS database cluster ARN to run SQL queries against, in any AWS partition. " +
+					"The SQL queries are sent to the cluster region. Required unless the `database_connection` block is set",
+				Optional:   true,
+				CustomType: ArnType{Kind: arnKindRdsCluster},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfConnectionChanged(),
 				},
 			},
 			"database_secret_arn": schema.StringAttribute{
-				MarkdownDescription: "The RDS database secret ARN to use for authentication",
-				Required:            true,
+				MarkdownDescription: "The RDS database secret ARN to use for authentication. Required unless the `database_connection` block is set",
+				Optional:            true,
+				CustomType:          ArnType{Kind: arnKindSecretsManagerSecret},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfConnectionChanged(),
+				},
+			},
+			"rename_strategy": schema.StringAttribute{
+				MarkdownDescription: "How `user` and `host` changes are applied: `replace` drops the account and creates a new one " +
+					"(losing its privileges), `rename` renames the account in place (`RENAME USER`), keeping its privileges. " +
+					"Defaults to `replace`",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(mysqlUserRenameStrategyReplace),
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						mysqlUserRenameStrategyReplace,
+						mysqlUserRenameStrategyRename,
+					),
+				},
+			},
+			"account_locked": schema.BoolAttribute{
+				MarkdownDescription: "Whether the MySQL user account is locked (`ACCOUNT LOCK`) or not (`ACCOUNT UNLOCK`). Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"deletion_mode": schema.StringAttribute{
+				MarkdownDescription: "What happens to the MySQL user when the resource is destroyed: " +
+					"`drop` removes the account, `lock` only locks it and `abandon` leaves it untouched. The `managed_secret` " +
+					"secret is deleted along with the account, but kept with `abandon` (the account credentials remain valid). " +
+					"Defaults to `drop`",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(mysqlUserDeletionModeDrop),
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						mysqlUserDeletionModeDrop,
+						mysqlUserDeletionModeLock,
+						mysqlUserDeletionModeAbandon,
+					),
+				},
+			},
+			"password_rotation_mode": schema.StringAttribute{
+				MarkdownDescription: "How password changes are applied: `replace` invalidates the old password immediately, " +
+					"`retain_current_password` keeps the old password valid as a secondary password until it is discarded " +
+					"(requires MySQL >= 8.0.14, checked when planning). " +
+					"Defaults to `replace`",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(mysqlUserPasswordRotationReplace),
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						mysqlUserPasswordRotationReplace,
+						mysqlUserPasswordRotationRetain,
+					),
+				},
+			},
+			"discard_old_password_after": schema.StringAttribute{
+				MarkdownDescription: "How long a retained password stays valid after a rotation (e.g. `24h`) before " +
+					"`DISCARD OLD PASSWORD` is issued. When not set, the old password is discarded on the next apply",
+				Optional: true,
 				Validators: []validator.String{
 					stringvalidator.RegexMatches(
-						regexp.MustCompile(`^arn:aws:secretsmanager:.*\w-.*\w-.*\d:.*\d:secret:.*\w|[-,_]$`),
-						"must contain a valid ARN resource value",
+						regexp.MustCompile(`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`),
+						"must contain a valid duration value (e.g. 30m, 24h)",
 					),
 				},
+			},
+			"password_rotation_phase": schema.StringAttribute{
+				MarkdownDescription: "The password rotation phase the account is in: `single_password` or `dual_password` " +
+					"(the previous password is still accepted)",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"password_rotated_at": schema.StringAttribute{
+				MarkdownDescription: "The RFC3339 timestamp of the last password rotation that retained the old password",
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"database_connection": connectionBlockSchema(),
+			"managed_secret": schema.SingleNestedBlock{
+				MarkdownDescription: "An AWS Secrets Manager secret created and maintained by the provider, holding the user " +
+					"credentials in the RDS rotation-compatible JSON format (`engine`, `host`, `port`, `username`, `password`, " +
+					"`dbClusterIdentifier`). The secret is updated on password changes and deleted (without recovery) on destroy",
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						MarkdownDescription: "The name of the secret",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.LengthBetween(1, 512),
+						},
+					},
+					"kms_key_id": schema.StringAttribute{
+						MarkdownDescription: "The ARN, key ID or alias of the KMS key used to encrypt the secret. " +
+							"Defaults to the `aws/secretsmanager` AWS managed key",
+						Optional: true,
+					},
+					"description": schema.StringAttribute{
+						MarkdownDescription: "The description of the secret",
+						Optional:            true,
+					},
+					"arn": schema.StringAttribute{
+						MarkdownDescription: "The ARN of the secret",
+						Computed:            true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
 				},
 			},
 		},
 	}
 }
 
+func (r *MysqlUserResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return append(connectionConfigValidators(),
+		// the password is generated when none is configured
+		resourcevalidator.Conflicting(
+			path.MatchRoot("password"),
+			path.MatchRoot("password_wo"),
+			path.MatchRoot("password_secret_arn"),
+			path.MatchRoot("auth_string_hash"),
+		),
+		// the managed secret holds the plaintext password, which is unknown for pre-hashed passwords
+		resourcevalidator.Conflicting(
+			path.MatchRoot("auth_string_hash"),
+			path.MatchRoot("managed_secret"),
+		),
+	)
+}
+
 func (r *MysqlUserResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*rdsdata.Client)
+	providerData, ok := req.ProviderData.(*RdsDataProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *rdsdata.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *RdsDataProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = providerData
+}
+
+func (r *MysqlUserResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Only protect the provider database user on resource destruction
+	if req.Plan.Raw.IsNull() {
+		var state MysqlUserResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if r.providerData != nil && !resp.Diagnostics.HasError() && state.DeletionMode.ValueString() != mysqlUserDeletionModeAbandon {
+			resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, state.connection(), state.User.ValueString(), path.Root("user"))...)
+		}
+
+		if r.providerData != nil && r.providerData.SqlPreview && !resp.Diagnostics.HasError() {
+			addSQLPreview(&resp.Diagnostics, []string{
+				deleteUserStatement(state.User.ValueString(), state.Host.ValueString(), state.DeletionMode.ValueString()),
+			})
+		}
+
+		return
+	}
+
+	var plan, state MysqlUserResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ======================= Provider policy =======================
+
+	if r.providerData != nil && !plan.User.IsUnknown() && !plan.Host.IsUnknown() {
+		if err := r.providerData.Policy.checkAccount(plan.User.ValueString(), plan.Host.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("user"), "Provider policy violation", err.Error())
+			return
+		}
+	}
+
+	if r.providerData != nil && (req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw)) {
+		resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, plan.connection(), plan.User.ValueString(), path.Root("user"))...)
+
+		// Renaming (or replacing) the provider database user cuts the provider off the database as well
+		if !req.State.Raw.IsNull() && (!plan.User.Equal(state.User) || !plan.Host.Equal(state.Host)) {
+			resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, state.connection(), state.User.ValueString(), path.Root("user"))...)
+		}
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// ======================= Plan validation =======================
+
+		resp.Diagnostics.Append(r.providerData.validateOnPlan(ctx, plan.connection(), accountRequiredPrivileges)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// ======================= Server capabilities =======================
+
+		if plan.PasswordRotation.ValueString() == mysqlUserPasswordRotationRetain {
+			resp.Diagnostics.Append(r.providerData.requireCapability(ctx, plan.connection(), capabilityDualPasswords, path.Root("password_rotation_mode"))...)
+		}
+
+		if !plan.Host.IsUnknown() {
+			resp.Diagnostics.Append(r.providerData.requireHostCapabilities(ctx, plan.connection(), []string{plan.Host.ValueString()}, path.Root("host"))...)
+		}
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// ======================= Write-only password fingerprint =======================
+
+	var passwordWo types.String
+
+	// Write-only values are only available in the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &passwordWo)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case passwordWo.IsNull():
+		plan.PasswordHash = types.StringNull()
+	case passwordWo.IsUnknown():
+		plan.PasswordHash = types.StringUnknown()
+	case passwordMatchesFingerprint(passwordWo.ValueString(), state.PasswordHash.ValueString()):
+		plan.PasswordHash = state.PasswordHash
+	default:
+		fingerprint, err := passwordFingerprint(passwordWo.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("password_hash"), "Password fingerprint error", err.Error())
+			return
+		}
+
+		plan.PasswordHash = types.StringValue(fingerprint)
+	}
+
+	// ======================= Generated password =======================
+
+	switch {
+	case !passwordWo.IsNull() || !plan.Password.IsNull() || !plan.PasswordSecretArn.IsNull() || !plan.AuthStringHash.IsNull():
+		plan.GeneratedPassword = types.StringNull()
+	case !state.GeneratedPassword.IsNull() && plan.PasswordKeepers.Equal(state.PasswordKeepers):
+		// Only regenerate the password when the keepers change, the generator settings apply to the next one
+		plan.GeneratedPassword = state.GeneratedPassword
+	default:
+		plan.GeneratedPassword = types.StringUnknown()
+	}
+
+	// ======================= Password drift =======================
+
+	// A password changed outside terraform is always restored
+	plan.PasswordInSync = types.BoolValue(true)
+
+	// ======================= Password secret version =======================
+
+	// Resolve the secret version to use, so a rotated secret shows up as a pending password change
+	if plan.PasswordSecretArn.IsNull() {
+		plan.PasswordSecretVersionId = types.StringNull()
+	} else if plan.PasswordSecretArn.IsUnknown() || plan.PasswordSecretJsonKey.IsUnknown() ||
+		plan.PasswordSecretVersionStage.IsUnknown() || r.providerData == nil {
+		plan.PasswordSecretVersionId = types.StringUnknown()
+	} else {
+		secretVersionId, err := r.passwordSecretVersionId(ctx, &plan)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("password_secret_arn"),
+				"Unable to read the MySQL user password secret",
+				err.Error(),
+			)
+			return
+		}
+
+		plan.PasswordSecretVersionId = types.StringValue(secretVersionId)
+	}
+
+	// ======================= Account rename =======================
+
+	renamed := !req.State.Raw.IsNull() && state.User.ValueString() != "" &&
+		(!plan.User.Equal(state.User) || !plan.Host.Equal(state.Host))
+
+	// Replaced accounts are dropped and created, not renamed
+	if renamed && len(resp.RequiresReplace) == 0 {
+		resp.Diagnostics.AddWarning(
+			"MySQL user renamed in place",
+			fmt.Sprintf(
+				"The '%s'@'%s' MySQL account will be renamed to '%s'@'%s' (RENAME USER), keeping its privileges. "+
+					"Resources referencing the old account that are not derived from this resource "+
+					"(e.g. awsrdsdata_mysql_grant resources with hardcoded user and host values) must be updated as well, "+
+					"otherwise they will fail or re-create the old account privileges.",
+				state.User.ValueString(),
+				state.Host.ValueString(),
+				plan.User.ValueString(),
+				plan.Host.ValueString(),
+			),
+		)
+	}
+
+	// ======================= Managed secret =======================
+
+	// A renamed secret is a brand new secret
+	if plan.ManagedSecret != nil && state.ManagedSecret != nil && !plan.ManagedSecret.Name.Equal(state.ManagedSecret.Name) {
+		plan.ManagedSecret.Arn = types.StringUnknown()
+	}
+
+	// ======================= Password rotation phase =======================
+
+	if !req.State.Raw.IsNull() {
+		if passwordChanged(plan, state) {
+			if plan.PasswordRotation.ValueString() == mysqlUserPasswordRotationRetain {
+				plan.RotationPhase = types.StringValue(mysqlUserPasswordPhaseDual)
+				plan.PasswordRotatedAt = types.StringUnknown()
+			} else {
+				plan.RotationPhase = types.StringValue(mysqlUserPasswordPhaseSingle)
+			}
+		} else if state.RotationPhase.ValueString() == mysqlUserPasswordPhaseDual {
+			discardOldPassword, err := oldPasswordDiscardDue(state.PasswordRotatedAt, plan.DiscardOldAfter)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("discard_old_password_after"),
+					"Invalid password discard delay",
+					err.Error(),
+				)
+				return
+			}
+
+			if discardOldPassword {
+				plan.RotationPhase = types.StringValue(mysqlUserPasswordPhaseSingle)
+			}
+		}
+	}
+
+	// ======================= SQL preview =======================
+
+	if r.providerData != nil && r.providerData.SqlPreview {
+		addSQLPreview(&resp.Diagnostics, plannedUserStatements(plan, state, req.State.Raw.IsNull(), len(resp.RequiresReplace) > 0))
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
 }
 
 func (r *MysqlUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -147,30 +670,66 @@ func (r *MysqlUserResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	// Write-only values are only available in the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &plan.PasswordWo)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// ======================= Resource CREATE Logic =======================
 
-	createUserSqlQuery := fmt.Sprintf(
-		"CREATE USER IF NOT EXISTS '%s'@'%s' IDENTIFIED BY '%s'",
-		plan.User.ValueString(),
-		plan.Host.ValueString(),
-		plan.Password.ValueString(),
-	)
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user", plan.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource CREATE operation error", err)
+		return
+	}
+
+	if generateErr := r.generatePassword(ctx, &plan); generateErr != nil {
+		addOperationError(&resp.Diagnostics, "Resource CREATE operation error", generateErr)
+		return
+	}
+
+	identification, identificationErr := r.identificationClause(ctx, &plan)
+
+	if identificationErr != nil {
+		addOperationError(&resp.Diagnostics, "Resource CREATE operation error", identificationErr)
+		return
+	}
+
+	createUserSqlQuery := createUserStatement(plan.User.ValueString(), plan.Host.ValueString(), identification, plan.AccountLocked.ValueBool())
 
 	createUserStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(plan.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(plan.DatabaseSecretArn.ValueString()),
-		Sql:         &createUserSqlQuery,
+		Sql: &createUserSqlQuery,
 	}
 
-	_, createUserSqlQueryErr := r.client.ExecuteStatement(ctx, &createUserStatementOpts)
+	_, createUserSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &createUserStatementOpts)
 
 	if createUserSqlQueryErr != nil {
-		resp.Diagnostics.AddError("Resource CREATE operation error", createUserSqlQueryErr.Error())
+		addOperationError(&resp.Diagnostics, "Resource CREATE operation error", createUserSqlQueryErr)
 		return
 	}
 
 	tflog.Trace(ctx, "created a MySQL user resource")
 
+	plan.RotationPhase = types.StringValue(mysqlUserPasswordPhaseSingle)
+	plan.PasswordRotatedAt = types.StringNull()
+	plan.PasswordInSync = types.BoolValue(true)
+
+	if plan.ManagedSecret != nil {
+		if err := r.createManagedSecret(ctx, &plan); err != nil {
+			addOperationError(&resp.Diagnostics, "Resource CREATE operation error", err)
+
+			// The account exists: keep track of it, so the next apply replaces it instead of losing it
+			partial := plan
+			partial.ManagedSecret = nil
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &partial)...)
+			return
+		}
+	}
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -187,30 +746,28 @@ func (r *MysqlUserResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	// ======================= Resource READ Logic =======================
 
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user", state.auditId())
+
 	userSqlQuery := fmt.Sprintf(
-		"SELECT user,host FROM mysql.user WHERE user='%s' AND host='%s'",
+		"SELECT user,host,account_locked,plugin,HEX(authentication_string) FROM mysql.user WHERE user='%s' AND host='%s'",
 		state.User.ValueString(),
 		state.Host.ValueString(),
 	)
 	userQueryStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(state.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(state.DatabaseSecretArn.ValueString()),
-		Sql:         &userSqlQuery,
+		Sql: &userSqlQuery,
 	}
 
-	userSqlQueryResult, userSqlQueryErr := r.client.ExecuteStatement(ctx, &userQueryStatementOpts)
+	userSqlQueryResult, userSqlQueryErr := r.providerData.QueryStatement(ctx, state.connection(), &userQueryStatementOpts)
 
 	if userSqlQueryErr != nil {
-		resp.Diagnostics.AddError("Resource READ operation error", userSqlQueryErr.Error())
+		addOperationError(&resp.Diagnostics, "Resource READ operation error", userSqlQueryErr)
 		return
 	}
 
-	if userSqlQueryResult.Records != nil && len(userSqlQueryResult.Records) == 0 {
+	if len(userSqlQueryResult.Records) == 0 {
 		tflog.Trace(ctx, "MySQL server returned no user records")
 		// Force resource recreation if user was deleted outside terraform
-		state.User = types.StringValue("")
-		state.Host = types.StringValue("")
-		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
@@ -232,18 +789,75 @@ func (r *MysqlUserResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	accountLockedRecord, ok := userSqlQueryResult.Records[0][2].(*rdsdatatypes.FieldMemberStringValue)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Resource READ operation error",
+			"MySQL `account_locked` type assertion error: check response returned from the AWS rdsdata service API call",
+		)
+		return
+	}
+
+	pluginRecord, ok := userSqlQueryResult.Records[0][3].(*rdsdatatypes.FieldMemberStringValue)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Resource READ operation error",
+			"MySQL `plugin` type assertion error: check response returned from the AWS rdsdata service API call",
+		)
+		return
+	}
+
+	authStringRecord, ok := userSqlQueryResult.Records[0][4].(*rdsdatatypes.FieldMemberStringValue)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Resource READ operation error",
+			"MySQL `authentication_string` type assertion error: check response returned from the AWS rdsdata service API call",
+		)
+		return
+	}
+
 	state.User = types.StringValue(userRecord.Value)
-	state.Host = types.StringValue(hostRecord.Value)
+	state.Host = NewHostPatternValue(hostRecord.Value)
+	state.AccountLocked = types.BoolValue(accountLockedRecord.Value == "Y")
+
+	// Detect passwords changed outside terraform
+	if password, ok := r.knownPassword(ctx, &state); ok {
+		passwordMatches, pluginSupported := authStringMatchesPassword(pluginRecord.Value, authStringRecord.Value, password)
+
+		if pluginSupported && !passwordMatches {
+			tflog.Trace(ctx, "MySQL user password changed outside terraform")
+		}
+
+		state.PasswordInSync = types.BoolValue(passwordMatches || !pluginSupported)
+	}
+
+	// Detect pre-hashed authentication strings changed outside terraform
+	if !state.AuthStringHash.IsNull() {
+		state.AuthPlugin = types.StringValue(pluginRecord.Value)
+
+		if !authStringMatches(state.AuthStringHash.ValueString(), authStringRecord.Value) {
+			tflog.Trace(ctx, "MySQL user authentication string changed outside terraform")
+			state.AuthStringHash = types.StringValue(formatAuthString(state.AuthStringHash.ValueString(), authStringRecord.Value))
+		}
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *MysqlUserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan MysqlUserResourceModel
+	var plan, state MysqlUserResourceModel
 
-	// Read Terraform plan data into the model
+	// Read Terraform plan and prior state data into the models
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only values are only available in the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &plan.PasswordWo)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -251,26 +865,141 @@ func (r *MysqlUserResource) Update(ctx context.Context, req resource.UpdateReque
 
 	// ======================= Resource UPDATE Logic =======================
 
-	updateUserSqlQuery := fmt.Sprintf(
-		"ALTER USER '%s'@'%s' IDENTIFIED BY '%s'",
-		plan.User.ValueString(),
-		plan.Host.ValueString(),
-		plan.Password.ValueString(),
-	)
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user", plan.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource UPDATE operation error", err)
+		return
+	}
+
+	// Resume an update which failed halfway: partial holds the state of the completed steps, which is saved along
+	// with the progress when a step fails
+	progress, progressDiags := loadOperationProgress(ctx, resp.Private, operationTarget(plan.User.ValueString(), plan.Host.ValueString()))
+	resp.Diagnostics.Append(progressDiags...)
+
+	partial := state
+
+	failUpdate := func(err error) {
+		addOperationError(&resp.Diagnostics, "Resource UPDATE operation error", err)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &partial)...)
+		resp.Diagnostics.Append(progress.save(ctx, resp.Private)...)
+	}
+
+	// Rename the account first, so the remaining statements target the new account
+	renameUser := !plan.User.Equal(state.User) || !plan.Host.Equal(state.Host)
+
+	if renameUser {
+		renameUserSqlQuery := renameUserStatement(state.User.ValueString(), state.Host.ValueString(), plan.User.ValueString(), plan.Host.ValueString())
+
+		renameUserStatementOpts := rdsdata.ExecuteStatementInput{
+			Sql: &renameUserSqlQuery,
+		}
 
-	updateUserStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(plan.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(plan.DatabaseSecretArn.ValueString()),
-		Sql:         &updateUserSqlQuery,
+		_, renameUserSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &renameUserStatementOpts)
+
+		if renameUserSqlQueryErr != nil {
+			failUpdate(renameUserSqlQueryErr)
+			return
+		}
+
+		partial.User, partial.Host = plan.User, plan.Host
+		progress.complete("rename")
+	}
+
+	rotatePassword := passwordChanged(plan, state)
+	retainCurrentPassword := rotatePassword && plan.PasswordRotation.ValueString() == mysqlUserPasswordRotationRetain
+
+	// Drop the retained password first when it's due (a new rotation retaining the current password replaces it anyway)
+	if discardOldPasswordDue(plan, state) {
+		discardPasswordSqlQuery := discardOldPasswordStatement(plan.User.ValueString(), plan.Host.ValueString())
+
+		discardPasswordStatementOpts := rdsdata.ExecuteStatementInput{
+			Sql: &discardPasswordSqlQuery,
+		}
+
+		_, discardPasswordSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &discardPasswordStatementOpts)
+
+		if discardPasswordSqlQueryErr != nil {
+			failUpdate(discardPasswordSqlQueryErr)
+			return
+		}
+
+		plan.RotationPhase = types.StringValue(mysqlUserPasswordPhaseSingle)
+		partial.RotationPhase = plan.RotationPhase
+	}
+
+	if plan.RotationPhase.IsUnknown() || plan.RotationPhase.IsNull() {
+		plan.RotationPhase = types.StringValue(mysqlUserPasswordPhaseSingle)
+	}
+
+	if plan.PasswordRotatedAt.IsUnknown() {
+		plan.PasswordRotatedAt = state.PasswordRotatedAt
 	}
 
-	_, updateUserSqlQueryErr := r.client.ExecuteStatement(ctx, &updateUserStatementOpts)
+	if rotatePassword {
+		if generateErr := r.generatePassword(ctx, &plan); generateErr != nil {
+			failUpdate(generateErr)
+			return
+		}
 
-	if updateUserSqlQueryErr != nil {
-		resp.Diagnostics.AddError("Resource UPDATE operation error", updateUserSqlQueryErr.Error())
+		identification, identificationErr := r.identificationClause(ctx, &plan)
+
+		if identificationErr != nil {
+			failUpdate(identificationErr)
+			return
+		}
+
+		updateUserSqlQuery := alterUserPasswordStatement(plan.User.ValueString(), plan.Host.ValueString(), identification, retainCurrentPassword)
+
+		updateUserStatementOpts := rdsdata.ExecuteStatementInput{
+			Sql: &updateUserSqlQuery,
+		}
+
+		_, updateUserSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &updateUserStatementOpts)
+
+		if updateUserSqlQueryErr != nil {
+			failUpdate(updateUserSqlQueryErr)
+			return
+		}
+
+		if retainCurrentPassword {
+			plan.RotationPhase = types.StringValue(mysqlUserPasswordPhaseDual)
+			plan.PasswordRotatedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+		}
+
+		// Never repeat a rotation retaining the current password: the next apply would retain the new password instead
+		copyPasswordAttributes(&partial, &plan)
+		progress.complete("password")
+	}
+
+	// The managed secret holds the user name as well. It is refreshed when a previous apply renamed the user or
+	// changed its password, but failed before updating the secret.
+	refreshManagedSecret := rotatePassword || renameUser || progress.done("rename") || progress.done("password")
+
+	if err := r.updateManagedSecret(ctx, &state, &plan, refreshManagedSecret); err != nil {
+		failUpdate(err)
 		return
 	}
 
+	partial.ManagedSecret = plan.ManagedSecret
+
+	if !plan.AccountLocked.Equal(state.AccountLocked) {
+		lockUserSqlQuery := lockUserStatement(plan.User.ValueString(), plan.Host.ValueString(), plan.AccountLocked.ValueBool())
+
+		lockUserStatementOpts := rdsdata.ExecuteStatementInput{
+			Sql: &lockUserSqlQuery,
+		}
+
+		_, lockUserSqlQueryErr := r.providerData.ExecuteStatement(ctx, plan.connection(), &lockUserStatementOpts)
+
+		if lockUserSqlQueryErr != nil {
+			failUpdate(lockUserSqlQueryErr)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(progress.clear(ctx, resp.Private)...)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -287,27 +1016,428 @@ func (r *MysqlUserResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	// ======================= Resource DELETE Logic =======================
 
-	deleteUserSqlQuery := fmt.Sprintf(
-		"DROP USER IF EXISTS '%s'@'%s'",
-		state.User.ValueString(),
-		state.Host.ValueString(),
-	)
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user", state.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource DELETE operation error", err)
+		return
+	}
+
+	if state.DeletionMode.ValueString() == mysqlUserDeletionModeAbandon {
+		tflog.Trace(ctx, "abandoning MySQL user, removing it from Terraform state only")
+
+		// The account keeps its credentials, so does the secret holding them
+		if state.ManagedSecret != nil {
+			resp.Diagnostics.AddWarning(
+				"Managed secret abandoned",
+				fmt.Sprintf(
+					"The %s secret holding the credentials of the abandoned %s MySQL user is kept, and no longer managed by "+
+						"Terraform: delete it once the account is no longer used",
+					state.ManagedSecret.Arn.ValueString(), state.auditId(),
+				),
+			)
+		}
+
+		return
+	}
+
+	deleteUserSqlQuery := deleteUserStatement(state.User.ValueString(), state.Host.ValueString(), state.DeletionMode.ValueString())
 
 	deleteUserStatementOpts := rdsdata.ExecuteStatementInput{
-		ResourceArn: aws.String(state.DatabaseResourceArn.ValueString()),
-		SecretArn:   aws.String(state.DatabaseSecretArn.ValueString()),
-		Sql:         &deleteUserSqlQuery,
+		Sql: &deleteUserSqlQuery,
 	}
 
-	_, deleteUserSqlQueryErr := r.client.ExecuteStatement(ctx, &deleteUserStatementOpts)
+	_, deleteUserSqlQueryErr := r.providerData.ExecuteStatement(ctx, state.connection(), &deleteUserStatementOpts)
 
 	if deleteUserSqlQueryErr != nil {
-		resp.Diagnostics.AddError("Resource DELETE operation error", deleteUserSqlQueryErr.Error())
+		addOperationError(&resp.Diagnostics, "Resource DELETE operation error", deleteUserSqlQueryErr)
 		return
 	}
+
+	if state.ManagedSecret != nil {
+		if err := r.deleteManagedSecret(ctx, state.connection(), state.ManagedSecret); err != nil {
+			addOperationError(&resp.Diagnostics, "Resource DELETE operation error", err)
+			return
+		}
+	}
 }
 
 func (r *MysqlUserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// TO DO
 	//resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// createUserStatement returns the statement creating the account with the identification clause.
+func createUserStatement(user string, host string, identification string, locked bool) string {
+	statement := fmt.Sprintf("CREATE USER IF NOT EXISTS '%s'@'%s' %s", user, host, identification)
+
+	if locked {
+		statement += " " + accountLockClause(true)
+	}
+
+	return statement
+}
+
+// renameUserStatement returns the statement renaming the account, keeping its privileges.
+func renameUserStatement(fromUser string, fromHost string, toUser string, toHost string) string {
+	return fmt.Sprintf("RENAME USER '%s'@'%s' TO '%s'@'%s'", fromUser, fromHost, toUser, toHost)
+}
+
+// discardOldPasswordStatement returns the statement dropping the password retained by a dual password rotation.
+func discardOldPasswordStatement(user string, host string) string {
+	return fmt.Sprintf("ALTER USER '%s'@'%s' DISCARD OLD PASSWORD", user, host)
+}
+
+// alterUserPasswordStatement returns the statement changing the account credentials, optionally retaining the
+// current password as secondary password.
+func alterUserPasswordStatement(user string, host string, identification string, retainCurrentPassword bool) string {
+	statement := fmt.Sprintf("ALTER USER '%s'@'%s' %s", user, host, identification)
+
+	if retainCurrentPassword {
+		statement += " RETAIN CURRENT PASSWORD"
+	}
+
+	return statement
+}
+
+// lockUserStatement returns the statement locking or unlocking the account.
+func lockUserStatement(user string, host string, locked bool) string {
+	return fmt.Sprintf("ALTER USER '%s'@'%s' %s", user, host, accountLockClause(locked))
+}
+
+// deleteUserStatement returns the statement removing the account according to the deletion mode
+// (the `abandon` mode runs no statement).
+func deleteUserStatement(user string, host string, deletionMode string) string {
+	switch deletionMode {
+	case mysqlUserDeletionModeAbandon:
+		return ""
+	case mysqlUserDeletionModeLock:
+		// Keep the account (and its grants) around, but prevent any new logins
+		return fmt.Sprintf("ALTER USER IF EXISTS '%s'@'%s' ACCOUNT LOCK", user, host)
+	default:
+		return fmt.Sprintf("DROP USER IF EXISTS '%s'@'%s'", user, host)
+	}
+}
+
+// discardOldPasswordDue reports whether the password retained by a previous rotation is dropped on update
+// (a new rotation retaining the current password replaces it anyway).
+func discardOldPasswordDue(plan MysqlUserResourceModel, state MysqlUserResourceModel) bool {
+	retainCurrentPassword := passwordChanged(plan, state) && plan.PasswordRotation.ValueString() == mysqlUserPasswordRotationRetain
+
+	return state.RotationPhase.ValueString() == mysqlUserPasswordPhaseDual && !retainCurrentPassword &&
+		plan.RotationPhase.ValueString() != mysqlUserPasswordPhaseDual
+}
+
+// plannedUserStatements returns the statements Create (when create is set) or Update will run to apply the plan,
+// a replaced user is dropped then created. The credentials are not resolved at plan time.
+func plannedUserStatements(plan MysqlUserResourceModel, state MysqlUserResourceModel, create bool, replace bool) []string {
+	user, host := previewString(plan.User), previewString(plan.Host)
+
+	identification := "IDENTIFIED BY " + redactedSQLValue
+	if !plan.AuthStringHash.IsNull() {
+		identification = authStringIdentificationClause(previewString(plan.AuthPlugin), previewString(plan.AuthStringHash))
+	}
+
+	if create || replace {
+		var statements []string
+
+		if replace {
+			statements = append(statements, deleteUserStatement(state.User.ValueString(), state.Host.ValueString(), state.DeletionMode.ValueString()))
+		}
+
+		return append(statements, createUserStatement(user, host, identification, plan.AccountLocked.ValueBool()))
+	}
+
+	var statements []string
+
+	if !plan.User.Equal(state.User) || !plan.Host.Equal(state.Host) {
+		statements = append(statements, renameUserStatement(state.User.ValueString(), state.Host.ValueString(), user, host))
+	}
+
+	rotatePassword := passwordChanged(plan, state)
+
+	if discardOldPasswordDue(plan, state) {
+		statements = append(statements, discardOldPasswordStatement(user, host))
+	}
+
+	if rotatePassword {
+		retainCurrentPassword := plan.PasswordRotation.ValueString() == mysqlUserPasswordRotationRetain
+		statements = append(statements, alterUserPasswordStatement(user, host, identification, retainCurrentPassword))
+	}
+
+	if !plan.AccountLocked.Equal(state.AccountLocked) {
+		statements = append(statements, lockUserStatement(user, host, plan.AccountLocked.ValueBool()))
+	}
+
+	return statements
+}
+
+// accountLockClause returns the MySQL account locking option matching the given lock state.
+func accountLockClause(locked bool) string {
+	if locked {
+		return "ACCOUNT LOCK"
+	}
+
+	return "ACCOUNT UNLOCK"
+}
+
+// oldPasswordDiscardDue reports whether a password retained at rotatedAt must be discarded,
+// given the (optional) delay configured via the `discard_old_password_after` attribute.
+func oldPasswordDiscardDue(rotatedAt types.String, discardAfter types.String) (bool, error) {
+	if discardAfter.IsNull() || discardAfter.IsUnknown() || rotatedAt.IsNull() || rotatedAt.IsUnknown() {
+		return true, nil
+	}
+
+	delay, err := time.ParseDuration(discardAfter.ValueString())
+	if err != nil {
+		return false, err
+	}
+
+	rotationTime, err := time.Parse(time.RFC3339, rotatedAt.ValueString())
+	if err != nil {
+		return false, fmt.Errorf("cannot parse the last password rotation timestamp: %w", err)
+	}
+
+	return time.Now().After(rotationTime.Add(delay)), nil
+}
+
+// identificationClause returns the `IDENTIFIED ...` clause setting the user credentials, either as
+// a plaintext password or as a pre-hashed authentication string.
+func (r *MysqlUserResource) identificationClause(ctx context.Context, model *MysqlUserResourceModel) (string, error) {
+	if !model.AuthStringHash.IsNull() {
+		return authStringIdentificationClause(model.AuthPlugin.ValueString(), model.AuthStringHash.ValueString()), nil
+	}
+
+	password, err := r.resolvePassword(ctx, model)
+	if err != nil {
+		return "", err
+	}
+
+	return "IDENTIFIED BY " + sqlStringLiteral(password), nil
+}
+
+// authStringIdentificationClause returns the `IDENTIFIED WITH ... AS ...` clause of a pre-hashed authentication string.
+func authStringIdentificationClause(authPlugin string, authString string) string {
+	// hex literals are valid string literals in MySQL and must not be quoted
+	if !isHexAuthString(authString) {
+		authString = sqlStringLiteral(authString)
+	}
+
+	return fmt.Sprintf("IDENTIFIED WITH %s AS %s", authPlugin, authString)
+}
+
+// generatePassword generates the user password when planned, honoring the server `validate_password` policy.
+func (r *MysqlUserResource) generatePassword(ctx context.Context, model *MysqlUserResourceModel) error {
+	if !model.GeneratedPassword.IsUnknown() {
+		return nil
+	}
+
+	policy, err := fetchPasswordPolicy(
+		ctx,
+		r.providerData,
+		model.connection(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to read the server password policy: %w", err)
+	}
+
+	password, err := generatePassword(int(model.PasswordLength.ValueInt64()), model.PasswordCharset.ValueString(), policy)
+	if err != nil {
+		return err
+	}
+
+	model.GeneratedPassword = types.StringValue(password)
+
+	return nil
+}
+
+// resolvePassword returns the password to set for the user, either from the configuration (`password`
+// or `password_wo`), from the `password_secret_arn` secret or the generated one. The secret version it was read from is
+// recorded into the model.
+func (r *MysqlUserResource) resolvePassword(ctx context.Context, model *MysqlUserResourceModel) (string, error) {
+	if !model.AuthStringHash.IsNull() {
+		return "", fmt.Errorf("the plaintext password is not known for users identified by a pre-hashed authentication string")
+	}
+
+	if !model.PasswordWo.IsNull() {
+		return model.PasswordWo.ValueString(), nil
+	}
+
+	if !model.GeneratedPassword.IsNull() {
+		return model.GeneratedPassword.ValueString(), nil
+	}
+
+	if model.PasswordSecretArn.IsNull() {
+		model.PasswordSecretVersionId = types.StringNull()
+		return model.Password.ValueString(), nil
+	}
+
+	if r.providerData == nil {
+		return "", fmt.Errorf("the AWS Secrets Manager client is not configured")
+	}
+
+	client, err := r.providerData.SecretsManagerClient(ctx, model.connection(), model.PasswordSecretArn.ValueString())
+	if err != nil {
+		return "", fmt.Errorf("unable to read the MySQL user password secret: %w", err)
+	}
+
+	password, secretVersionId, err := getSecretString(ctx, client, secretsManagerSecret{
+		Arn: model.PasswordSecretArn.ValueString(),
+		// Stick to the secret version resolved at plan time (if any)
+		VersionId:    model.PasswordSecretVersionId.ValueString(),
+		VersionStage: model.PasswordSecretVersionStage.ValueString(),
+		JsonKey:      model.PasswordSecretJsonKey.ValueString(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to read the MySQL user password secret: %w", err)
+	}
+
+	// The secret value is not checked by the attribute validators
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return "", fmt.Errorf(
+			"the MySQL user password read from the %s secret must be at least %d characters long",
+			model.PasswordSecretArn.ValueString(), minPasswordLength,
+		)
+	}
+
+	model.PasswordSecretVersionId = types.StringValue(secretVersionId)
+
+	return password, nil
+}
+
+// passwordSecretVersionId resolves the ID of the `password_secret_arn` secret version to read the password from,
+// the secret value itself is only read on apply.
+func (r *MysqlUserResource) passwordSecretVersionId(ctx context.Context, model *MysqlUserResourceModel) (string, error) {
+	client, err := r.providerData.SecretsManagerClient(ctx, model.connection(), model.PasswordSecretArn.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	return getSecretVersionId(ctx, client, secretsManagerSecret{
+		Arn:          model.PasswordSecretArn.ValueString(),
+		VersionStage: model.PasswordSecretVersionStage.ValueString(),
+	})
+}
+
+// knownPassword returns the plaintext password the user is expected to have, when it can be known outside
+// of an apply (write-only passwords and pre-hashed authentication strings cannot be checked).
+func (r *MysqlUserResource) knownPassword(ctx context.Context, model *MysqlUserResourceModel) (string, bool) {
+	switch {
+	case !model.GeneratedPassword.IsNull():
+		return model.GeneratedPassword.ValueString(), true
+	case !model.Password.IsNull():
+		return model.Password.ValueString(), true
+	case !model.PasswordSecretArn.IsNull() && r.providerData != nil:
+		client, err := r.providerData.SecretsManagerClient(ctx, model.connection(), model.PasswordSecretArn.ValueString())
+		if err != nil {
+			tflog.Warn(ctx, "unable to read the MySQL user password secret, skipping password drift detection", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return "", false
+		}
+
+		password, _, err := getSecretString(ctx, client, secretsManagerSecret{
+			Arn:          model.PasswordSecretArn.ValueString(),
+			VersionId:    model.PasswordSecretVersionId.ValueString(),
+			VersionStage: model.PasswordSecretVersionStage.ValueString(),
+			JsonKey:      model.PasswordSecretJsonKey.ValueString(),
+		})
+		if err != nil {
+			tflog.Warn(ctx, "unable to read the MySQL user password secret, skipping password drift detection", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return "", false
+		}
+
+		return password, true
+	}
+
+	return "", false
+}
+
+// copyPasswordAttributes copies the attributes describing the user password from src to dst.
+func copyPasswordAttributes(dst *MysqlUserResourceModel, src *MysqlUserResourceModel) {
+	dst.Password = src.Password
+	dst.PasswordVersion = src.PasswordVersion
+	dst.PasswordHash = src.PasswordHash
+	dst.AuthStringHash = src.AuthStringHash
+	dst.AuthPlugin = src.AuthPlugin
+	dst.PasswordLength = src.PasswordLength
+	dst.PasswordCharset = src.PasswordCharset
+	dst.PasswordKeepers = src.PasswordKeepers
+	dst.GeneratedPassword = src.GeneratedPassword
+	dst.PasswordInSync = src.PasswordInSync
+	dst.PasswordRotation = src.PasswordRotation
+	dst.RotationPhase = src.RotationPhase
+	dst.PasswordRotatedAt = src.PasswordRotatedAt
+	dst.PasswordSecretArn = src.PasswordSecretArn
+	dst.PasswordSecretJsonKey = src.PasswordSecretJsonKey
+	dst.PasswordSecretVersionStage = src.PasswordSecretVersionStage
+	dst.PasswordSecretVersionId = src.PasswordSecretVersionId
+}
+
+// passwordChanged reports whether the password planned for the user differs from the one in the prior state.
+func passwordChanged(plan MysqlUserResourceModel, state MysqlUserResourceModel) bool {
+	return !plan.Password.Equal(state.Password) ||
+		!plan.PasswordVersion.Equal(state.PasswordVersion) ||
+		!plan.PasswordHash.Equal(state.PasswordHash) ||
+		!plan.AuthStringHash.Equal(state.AuthStringHash) ||
+		!plan.AuthPlugin.Equal(state.AuthPlugin) ||
+		!plan.GeneratedPassword.Equal(state.GeneratedPassword) ||
+		(!state.PasswordInSync.IsNull() && !state.PasswordInSync.ValueBool()) ||
+		!plan.PasswordSecretArn.Equal(state.PasswordSecretArn) ||
+		!plan.PasswordSecretJsonKey.Equal(state.PasswordSecretJsonKey) ||
+		!plan.PasswordSecretVersionId.Equal(state.PasswordSecretVersionId)
+}
+
+// isHexAuthString reports whether the authentication string is given as a hex literal (`0x...`).
+func isHexAuthString(authString string) bool {
+	if len(authString) < 3 || !strings.HasPrefix(strings.ToLower(authString), "0x") {
+		return false
+	}
+
+	_, err := hex.DecodeString(authString[2:])
+
+	return err == nil
+}
+
+// authStringMatches reports whether the configured authentication string matches the hex encoded
+// `mysql.user.authentication_string` value.
+func authStringMatches(authString string, hexAuthString string) bool {
+	if isHexAuthString(authString) {
+		return strings.EqualFold(authString[2:], hexAuthString)
+	}
+
+	return strings.EqualFold(hex.EncodeToString([]byte(authString)), hexAuthString)
+}
+
+// formatAuthString renders the hex encoded `mysql.user.authentication_string` value the same way
+// (hex literal or plain string) as the configured authentication string.
+func formatAuthString(authString string, hexAuthString string) string {
+	if isHexAuthString(authString) {
+		return "0x" + strings.ToUpper(hexAuthString)
+	}
+
+	decodedAuthString, err := hex.DecodeString(hexAuthString)
+	if err != nil {
+		return hexAuthString
+	}
+
+	return string(decodedAuthString)
+}
+
+// requiresReplaceUnlessRenamed returns a plan modifier requiring the resource replacement on changes,
+// unless the `rename_strategy` attribute allows renaming the account in place.
+func requiresReplaceUnlessRenamed() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			var renameStrategy types.String
+
+			resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rename_strategy"), &renameStrategy)...)
+
+			resp.RequiresReplace = renameStrategy.ValueString() != mysqlUserRenameStrategyRename
+		},
+		"Replaces the resource on changes, unless rename_strategy is set to rename.",
+		"Replaces the resource on changes, unless `rename_strategy` is set to `rename`.",
+	)
+}
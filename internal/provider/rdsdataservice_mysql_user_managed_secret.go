@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretsmanagertypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The KMS key used by Secrets Manager when no customer managed key is configured.
+const defaultSecretsManagerKmsKeyId = "alias/aws/secretsmanager"
+
+// MysqlUserManagedSecretModel describes the `managed_secret` block data model.
+type MysqlUserManagedSecretModel struct {
+	Name        types.String `tfsdk:"name"`
+	KmsKeyId    types.String `tfsdk:"kms_key_id"`
+	Description types.String `tfsdk:"description"`
+	Arn         types.String `tfsdk:"arn"`
+}
+
+// managedSecretString renders the managed secret value holding the user credentials.
+func (r *MysqlUserResource) managedSecretString(ctx context.Context, model *MysqlUserResourceModel) (string, error) {
+	if r.providerData == nil {
+		return "", fmt.Errorf("the AWS RDS and Secrets Manager clients are not configured")
+	}
+
+	password, err := r.resolvePassword(ctx, model)
+	if err != nil {
+		return "", err
+	}
+
+	rdsClient, err := r.providerData.RdsClient(ctx, model.connection())
+	if err != nil {
+		return "", err
+	}
+
+	clusterEndpoint, err := describeClusterEndpoint(ctx, rdsClient, model.connection().ResourceArn)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the RDS cluster endpoint for the managed secret: %w", err)
+	}
+
+	return rdsCredentialsSecret{
+		Engine:              "mysql",
+		Host:                clusterEndpoint.Host,
+		Port:                clusterEndpoint.Port,
+		Username:            model.User.ValueString(),
+		Password:            password,
+		DbClusterIdentifier: clusterEndpoint.ClusterIdentifier,
+	}.secretString()
+}
+
+// createManagedSecret creates the `managed_secret` secret and records its ARN into the model.
+func (r *MysqlUserResource) createManagedSecret(ctx context.Context, model *MysqlUserResourceModel) error {
+	secretString, err := r.managedSecretString(ctx, model)
+	if err != nil {
+		return err
+	}
+
+	createSecretOpts := secretsmanager.CreateSecretInput{
+		Name:         aws.String(model.ManagedSecret.Name.ValueString()),
+		SecretString: aws.String(secretString),
+	}
+
+	if !model.ManagedSecret.KmsKeyId.IsNull() {
+		createSecretOpts.KmsKeyId = aws.String(model.ManagedSecret.KmsKeyId.ValueString())
+	}
+
+	if !model.ManagedSecret.Description.IsNull() {
+		createSecretOpts.Description = aws.String(model.ManagedSecret.Description.ValueString())
+	}
+
+	// the managed secret lives next to the database cluster
+	client, err := r.providerData.SecretsManagerClient(ctx, model.connection(), model.connection().ResourceArn)
+	if err != nil {
+		return err
+	}
+
+	secret, err := client.CreateSecret(ctx, &createSecretOpts)
+	if err != nil {
+		return fmt.Errorf("unable to create the managed secret: %w", err)
+	}
+
+	tflog.Trace(ctx, "created the MySQL user managed secret")
+
+	model.ManagedSecret.Arn = types.StringValue(aws.ToString(secret.ARN))
+
+	return nil
+}
+
+// updateManagedSecret reconciles the `managed_secret` secret with the planned configuration.
+func (r *MysqlUserResource) updateManagedSecret(ctx context.Context, state *MysqlUserResourceModel, plan *MysqlUserResourceModel, credentialsChanged bool) error {
+	switch {
+	case plan.ManagedSecret == nil && state.ManagedSecret == nil:
+		return nil
+	case plan.ManagedSecret == nil:
+		return r.deleteManagedSecret(ctx, state.connection(), state.ManagedSecret)
+	case state.ManagedSecret == nil:
+		return r.createManagedSecret(ctx, plan)
+	case !plan.ManagedSecret.Name.Equal(state.ManagedSecret.Name):
+		if err := r.deleteManagedSecret(ctx, state.connection(), state.ManagedSecret); err != nil {
+			return err
+		}
+
+		return r.createManagedSecret(ctx, plan)
+	}
+
+	plan.ManagedSecret.Arn = state.ManagedSecret.Arn
+
+	kmsKeyChanged := !plan.ManagedSecret.KmsKeyId.Equal(state.ManagedSecret.KmsKeyId)
+	descriptionChanged := !plan.ManagedSecret.Description.Equal(state.ManagedSecret.Description)
+
+	if !credentialsChanged && !kmsKeyChanged && !descriptionChanged {
+		return nil
+	}
+
+	updateSecretOpts := secretsmanager.UpdateSecretInput{
+		SecretId: aws.String(state.ManagedSecret.Arn.ValueString()),
+	}
+
+	if descriptionChanged {
+		updateSecretOpts.Description = aws.String(plan.ManagedSecret.Description.ValueString())
+	}
+
+	// Changing the KMS key only applies to new secret versions, so store the credentials again
+	if kmsKeyChanged {
+		updateSecretOpts.KmsKeyId = aws.String(defaultSecretsManagerKmsKeyId)
+
+		if !plan.ManagedSecret.KmsKeyId.IsNull() {
+			updateSecretOpts.KmsKeyId = aws.String(plan.ManagedSecret.KmsKeyId.ValueString())
+		}
+	}
+
+	if credentialsChanged || kmsKeyChanged {
+		secretString, err := r.managedSecretString(ctx, plan)
+		if err != nil {
+			return err
+		}
+
+		updateSecretOpts.SecretString = aws.String(secretString)
+	}
+
+	client, err := r.providerData.SecretsManagerClient(ctx, state.connection(), state.ManagedSecret.Arn.ValueString())
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.UpdateSecret(ctx, &updateSecretOpts); err != nil {
+		return fmt.Errorf("unable to update the managed secret: %w", err)
+	}
+
+	return nil
+}
+
+// deleteManagedSecret deletes the `managed_secret` secret right away (no recovery window).
+func (r *MysqlUserResource) deleteManagedSecret(ctx context.Context, connection databaseConnection, managedSecret *MysqlUserManagedSecretModel) error {
+	if managedSecret.Arn.IsNull() || managedSecret.Arn.IsUnknown() {
+		return nil
+	}
+
+	if r.providerData == nil {
+		return fmt.Errorf("the AWS Secrets Manager client is not configured")
+	}
+
+	deleteSecretOpts := secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(managedSecret.Arn.ValueString()),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	}
+
+	client, err := r.providerData.SecretsManagerClient(ctx, connection, managedSecret.Arn.ValueString())
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteSecret(ctx, &deleteSecretOpts)
+
+	var notFoundErr *secretsmanagertypes.ResourceNotFoundException
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return fmt.Errorf("unable to delete the managed secret: %w", err)
+	}
+
+	tflog.Trace(ctx, "deleted the MySQL user managed secret")
+
+	return nil
+}
@@ -0,0 +1,767 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/dcarbone/terraform-plugin-framework-utils/v3/conv"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &MysqlUserMultiHostResource{}
+	_ resource.ResourceWithImportState = &MysqlUserMultiHostResource{}
+	_ resource.ResourceWithModifyPlan  = &MysqlUserMultiHostResource{}
+
+	_ resource.ResourceWithConfigValidators = &MysqlUserMultiHostResource{}
+)
+
+// Values reported by the `host_status` attribute.
+const (
+	mysqlUserHostStatusPresent       = "present"
+	mysqlUserHostStatusMissing       = "missing"
+	mysqlUserHostStatusMissingGrants = "missing_grants"
+)
+
+func NewMysqlUserMultiHostResource() resource.Resource {
+	return &MysqlUserMultiHostResource{}
+}
+
+// MysqlUserMultiHostResource defines the resource implementation.
+type MysqlUserMultiHostResource struct {
+	providerData *RdsDataProviderData
+}
+
+// MysqlUserMultiHostResourceModel describes the resource data model.
+type MysqlUserMultiHostResourceModel struct {
+	User                types.String                   `tfsdk:"user"`
+	Password            types.String                   `tfsdk:"password"`
+	Hosts               types.Set                      `tfsdk:"hosts"`
+	HostStatus          types.Map                      `tfsdk:"host_status"`
+	DatabaseResourceArn ArnValue                       `tfsdk:"database_resource_arn"`
+	DatabaseSecretArn   ArnValue                       `tfsdk:"database_secret_arn"`
+	Connection          *DatabaseConnectionModel       `tfsdk:"database_connection"`
+	Grants              []MysqlUserMultiHostGrantModel `tfsdk:"grant"`
+}
+
+// connection returns the database connection of the resource.
+func (m *MysqlUserMultiHostResourceModel) connection() databaseConnection {
+	return newDatabaseConnection(m.DatabaseResourceArn, m.DatabaseSecretArn, m.Connection)
+}
+
+// auditId identifies the resource in the audit log.
+func (m *MysqlUserMultiHostResourceModel) auditId() string {
+	return fmt.Sprintf("'%s'", m.User.ValueString())
+}
+
+// MysqlUserMultiHostGrantModel describes the `grant` block data model.
+type MysqlUserMultiHostGrantModel struct {
+	Database   types.String `tfsdk:"database"`
+	Privileges types.List   `tfsdk:"privileges"`
+}
+
+func (r *MysqlUserMultiHostResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mysql_user_multi_host"
+}
+
+func (r *MysqlUserMultiHostResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "AWS RDS Data MySQL user resource spanning multiple host patterns",
+
+		Attributes: map[string]schema.Attribute{
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The MySQL user name to create",
+				Required:            true,
+				Validators: []validator.String{
+					// user value cannot be empty
+					stringvalidator.LengthAtLeast(1),
+					// protect against destroying system accounts
+					stringvalidator.NoneOf(builtinProtectedAccounts...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The MySQL password to set for the user on every host (must be at least 16 characters long)",
+				Required:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					// password must be at least 16 characters long
+					stringvalidator.LengthAtLeast(minPasswordLength),
+				},
+			},
+			"hosts": schema.SetAttribute{
+				MarkdownDescription: "The MySQL user host values, one account is created (and kept in sync) for each host",
+				Required:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					// at least one host must be defined
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(hostPatternValidator{}),
+				},
+			},
+			"host_status": schema.MapAttribute{
+				MarkdownDescription: "The status of the account on each host, as of the last refresh: `present`, " +
+					"`missing` (dropped outside Terraform) or `missing_grants` (privileges revoked outside Terraform)",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"database_resource_arn": schema.StringAttribute{
+				MarkdownDescription: "The RDS database cluster ARN to run SQL queries against, in any AWS partition. " +
+					"The SQL queries are sent to the cluster region. Required unless the `database_connection` block is set",
+				Optional:   true,
+				CustomType: ArnType{Kind: arnKindRdsCluster},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfConnectionChanged(),
+				},
+			},
+			"database_secret_arn": schema.StringAttribute{
+				MarkdownDescription: "The RDS database secret ARN to use for authentication. Required unless the `database_connection` block is set",
+				Optional:            true,
+				CustomType:          ArnType{Kind: arnKindSecretsManagerSecret},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfConnectionChanged(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"database_connection": connectionBlockSchema(),
+			"grant": schema.SetNestedBlock{
+				MarkdownDescription: "The privileges to grant on a database, applied consistently to the account on every host",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"database": schema.StringAttribute{
+							MarkdownDescription: "The MySQL database to grant privileges for",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+								// protect against destroying system databases
+								stringvalidator.NoneOf(builtinProtectedDatabases...),
+							},
+						},
+						"privileges": schema.ListAttribute{
+							MarkdownDescription: "The MySQL user privileges to grant, checked when planning against the privileges (and the levels " +
+								"they apply to) of MySQL, and of the cluster MySQL version for the privileges introduced or removed by a MySQL release",
+							Required:    true,
+							ElementType: types.StringType,
+							Validators: []validator.List{
+								// at least one privilege must be defined
+								listvalidator.SizeAtLeast(1),
+								// privilege definitions cannot be empty
+								listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *MysqlUserMultiHostResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return connectionConfigValidators()
+}
+
+func (r *MysqlUserMultiHostResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*RdsDataProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RdsDataProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *MysqlUserMultiHostResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.providerData == nil {
+		return
+	}
+
+	// Only protect the provider database user on resource destruction
+	if req.Plan.Raw.IsNull() {
+		var state MysqlUserMultiHostResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, state.connection(), state.User.ValueString(), path.Root("user"))...)
+		}
+
+		return
+	}
+
+	var plan, state MysqlUserMultiHostResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw) {
+		resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, plan.connection(), plan.User.ValueString(), path.Root("user"))...)
+	}
+
+	// Replacing the provider database user drops its accounts
+	if !req.State.Raw.IsNull() && !plan.User.Equal(state.User) {
+		resp.Diagnostics.Append(r.providerData.checkSelfManagement(ctx, state.connection(), state.User.ValueString(), path.Root("user"))...)
+	}
+
+	// ======================= Provider policy =======================
+
+	if !plan.User.IsUnknown() && !plan.Hosts.IsUnknown() {
+		for _, host := range conv.StringSetToStrings(plan.Hosts) {
+			if err := r.providerData.Policy.checkAccount(plan.User.ValueString(), host); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("hosts"), "Provider policy violation", err.Error())
+			}
+		}
+	}
+
+	for _, grant := range plan.Grants {
+		if grant.Database.IsUnknown() || grant.Privileges.IsUnknown() {
+			continue
+		}
+
+		if err := r.providerData.Policy.checkGrant(grant.Database.ValueString(), conv.StringListToStrings(grant.Privileges)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("grant"), "Provider policy violation", err.Error())
+		}
+	}
+
+	// ======================= Server capabilities =======================
+
+	if !resp.Diagnostics.HasError() && !plan.Hosts.IsUnknown() && (req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw)) {
+		resp.Diagnostics.Append(r.providerData.requireHostCapabilities(ctx, plan.connection(), conv.StringSetToStrings(plan.Hosts), path.Root("hosts"))...)
+	}
+
+	// ======================= Privileges =======================
+
+	if !resp.Diagnostics.HasError() && (req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw)) {
+		for _, grant := range plan.Grants {
+			if grant.Database.IsUnknown() || grant.Privileges.IsUnknown() {
+				continue
+			}
+
+			resp.Diagnostics.Append(r.providerData.validatePrivileges(
+				ctx,
+				plan.connection(),
+				grant.Database.ValueString(),
+				conv.StringListToStrings(grant.Privileges),
+				path.Root("grant"),
+			)...)
+		}
+	}
+
+	// ======================= Plan validation =======================
+
+	if !resp.Diagnostics.HasError() && (req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw)) {
+		required := slices.Clone(accountRequiredPrivileges)
+
+		for _, grant := range plan.Grants {
+			if !grant.Database.IsUnknown() && !grant.Privileges.IsUnknown() {
+				required = append(required, grantRequiredPrivileges(grant.Database.ValueString(), conv.StringListToStrings(grant.Privileges))...)
+			}
+		}
+
+		resp.Diagnostics.Append(r.providerData.validateOnPlan(ctx, plan.connection(), required)...)
+	}
+}
+
+func (r *MysqlUserMultiHostResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan MysqlUserMultiHostResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ======================= Resource CREATE Logic =======================
+
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user_multi_host", plan.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource CREATE operation error", err)
+		return
+	}
+
+	hosts := conv.StringSetToStrings(plan.Hosts)
+
+	for i, host := range hosts {
+		if err := r.createHostAccount(ctx, &plan, host); err != nil {
+			addOperationError(&resp.Diagnostics, "Resource CREATE operation error", err)
+
+			// Keep track of the accounts created so far (the failed one may exist as well), so the next apply
+			// replaces them instead of losing them
+			resp.Diagnostics.Append(r.savePartialHosts(ctx, &resp.State, plan, hosts[:i+1])...)
+			return
+		}
+	}
+
+	tflog.Trace(ctx, "created a multi host MySQL user resource")
+
+	plan.HostStatus = hostStatusMap(hosts, nil)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *MysqlUserMultiHostResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state MysqlUserMultiHostResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ======================= Resource READ Logic =======================
+
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user_multi_host", state.auditId())
+
+	hosts := conv.StringSetToStrings(state.Hosts)
+	quotedHosts := make([]string, 0, len(hosts))
+
+	// Match both the configured and the canonical host values, the results are compared in their canonical form
+	for _, host := range hosts {
+		quotedHosts = append(quotedHosts, fmt.Sprintf("'%s'", host))
+
+		if canonical := canonicalHostPattern(host); canonical != host {
+			quotedHosts = append(quotedHosts, fmt.Sprintf("'%s'", canonical))
+		}
+	}
+
+	userHostsSqlQuery := fmt.Sprintf(
+		"SELECT host FROM mysql.user WHERE user='%s' AND host IN (%s)",
+		state.User.ValueString(),
+		strings.Join(quotedHosts, ","),
+	)
+
+	userHostsQueryStatementOpts := rdsdata.ExecuteStatementInput{
+		Sql: &userHostsSqlQuery,
+	}
+
+	userHostsSqlQueryResult, userHostsSqlQueryErr := r.providerData.QueryStatement(ctx, state.connection(), &userHostsQueryStatementOpts)
+
+	if userHostsSqlQueryErr != nil {
+		addOperationError(&resp.Diagnostics, "Resource READ operation error", userHostsSqlQueryErr)
+		return
+	}
+
+	existingHosts := map[string]bool{}
+
+	for _, record := range userHostsSqlQueryResult.Records {
+		hostRecord, ok := record[0].(*rdsdatatypes.FieldMemberStringValue)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Resource READ operation error",
+				"MySQL `host` type assertion error: check response returned from the AWS rdsdata service API call",
+			)
+			return
+		}
+
+		existingHosts[canonicalHostPattern(hostRecord.Value)] = true
+	}
+
+	status := map[string]string{}
+	inSyncHosts := []string{}
+
+	for _, host := range hosts {
+		hostHasGrants := true
+
+		if existingHosts[canonicalHostPattern(host)] && len(state.Grants) > 0 {
+			var err error
+
+			if hostHasGrants, err = r.hasGrants(ctx, &state, host); err != nil {
+				addOperationError(&resp.Diagnostics, "Resource READ operation error", err)
+				return
+			}
+		}
+
+		switch {
+		case !existingHosts[canonicalHostPattern(host)]:
+			status[host] = mysqlUserHostStatusMissing
+		case !hostHasGrants:
+			status[host] = mysqlUserHostStatusMissingGrants
+		default:
+			status[host] = mysqlUserHostStatusPresent
+			inSyncHosts = append(inSyncHosts, host)
+		}
+
+		if status[host] != mysqlUserHostStatusPresent {
+			tflog.Trace(ctx, "MySQL user host account drifted", map[string]interface{}{"host": host, "status": status[host]})
+		}
+	}
+
+	if len(inSyncHosts) == 0 {
+		tflog.Trace(ctx, "MySQL server returned no user records")
+		// Force resource recreation if all the accounts were deleted outside terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Hosts drifted outside terraform are removed from the state, so the next apply re-creates them
+	hostsValue, diags := types.SetValueFrom(ctx, types.StringType, inSyncHosts)
+	resp.Diagnostics.Append(diags...)
+
+	state.Hosts = hostsValue
+	state.HostStatus = hostStatusMap(hosts, status)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *MysqlUserMultiHostResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state MysqlUserMultiHostResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ======================= Resource UPDATE Logic =======================
+
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user_multi_host", plan.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource UPDATE operation error", err)
+		return
+	}
+
+	plannedHosts := conv.StringSetToStrings(plan.Hosts)
+	plannedCanonicalHosts := make([]string, 0, len(plannedHosts))
+
+	for _, host := range plannedHosts {
+		plannedCanonicalHosts = append(plannedCanonicalHosts, canonicalHostPattern(host))
+	}
+
+	// Host values are compared in their canonical form, so `DB.example.com` and `db.example.com` are the same account
+	priorHosts := map[string]bool{}
+
+	for _, host := range conv.StringSetToStrings(state.Hosts) {
+		priorHosts[canonicalHostPattern(host)] = true
+	}
+
+	// Resume an update which failed halfway: the password and privileges of each host can't be told apart in the
+	// state, so the completed steps are recorded along with the accounts created or dropped so far
+	progress, progressDiags := loadOperationProgress(ctx, resp.Private, multiHostOperationTarget(plan))
+	resp.Diagnostics.Append(progressDiags...)
+
+	partialHosts := conv.StringSetToStrings(state.Hosts)
+
+	failUpdate := func(err error) {
+		addOperationError(&resp.Diagnostics, "Resource UPDATE operation error", err)
+		resp.Diagnostics.Append(r.savePartialHosts(ctx, &resp.State, state, partialHosts)...)
+		resp.Diagnostics.Append(progress.save(ctx, resp.Private)...)
+	}
+
+	// Drop the accounts of the hosts no longer configured
+	for _, host := range conv.StringSetToStrings(state.Hosts) {
+		if slices.Contains(plannedCanonicalHosts, canonicalHostPattern(host)) {
+			continue
+		}
+
+		if err := r.executeStatement(ctx, &state, fmt.Sprintf("DROP USER IF EXISTS '%s'@'%s'", state.User.ValueString(), host)); err != nil {
+			failUpdate(err)
+			return
+		}
+
+		partialHosts = slices.DeleteFunc(partialHosts, func(partialHost string) bool { return partialHost == host })
+	}
+
+	passwordChanged := !plan.Password.Equal(state.Password)
+	grantsChanged := !grantsEqual(plan.Grants, state.Grants)
+
+	for _, host := range plannedHosts {
+		// Accounts created by a previous apply already have the planned password and privileges
+		if progress.done("create:" + host) {
+			continue
+		}
+
+		// Create the accounts of the new (or drifted) hosts
+		if !priorHosts[canonicalHostPattern(host)] {
+			if err := r.createHostAccount(ctx, &plan, host); err != nil {
+				// The account may exist already
+				partialHosts = append(partialHosts, host)
+				failUpdate(err)
+				return
+			}
+
+			partialHosts = append(partialHosts, host)
+			progress.complete("create:" + host)
+
+			continue
+		}
+
+		if passwordChanged && !progress.done("password:"+host) {
+			updateUserSqlQuery := fmt.Sprintf(
+				"ALTER USER '%s'@'%s' IDENTIFIED BY %s",
+				plan.User.ValueString(),
+				host,
+				sqlStringLiteral(plan.Password.ValueString()),
+			)
+
+			if err := r.executeStatement(ctx, &plan, updateUserSqlQuery); err != nil {
+				failUpdate(err)
+				return
+			}
+
+			progress.complete("password:" + host)
+		}
+
+		if grantsChanged && !progress.done("grants:"+host) {
+			if err := r.revokeHostGrants(ctx, &state, host); err != nil {
+				failUpdate(err)
+				return
+			}
+
+			if err := r.grantHostPrivileges(ctx, &plan, host); err != nil {
+				failUpdate(err)
+				return
+			}
+
+			progress.complete("grants:" + host)
+		}
+	}
+
+	resp.Diagnostics.Append(progress.clear(ctx, resp.Private)...)
+
+	plan.HostStatus = hostStatusMap(plannedHosts, nil)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *MysqlUserMultiHostResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state MysqlUserMultiHostResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ======================= Resource DELETE Logic =======================
+
+	ctx = withAuditResource(ctx, "awsrdsdata_mysql_user_multi_host", state.auditId())
+
+	if err := r.providerData.checkWritable(); err != nil {
+		addOperationError(&resp.Diagnostics, "Resource DELETE operation error", err)
+		return
+	}
+
+	for _, host := range conv.StringSetToStrings(state.Hosts) {
+		if err := r.executeStatement(ctx, &state, fmt.Sprintf("DROP USER IF EXISTS '%s'@'%s'", state.User.ValueString(), host)); err != nil {
+			addOperationError(&resp.Diagnostics, "Resource DELETE operation error", err)
+			return
+		}
+	}
+}
+
+func (r *MysqlUserMultiHostResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// TO DO
+	//resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// executeStatement runs a single SQL statement against the model database.
+func (r *MysqlUserMultiHostResource) executeStatement(ctx context.Context, model *MysqlUserMultiHostResourceModel, sqlQuery string) error {
+	statementOpts := rdsdata.ExecuteStatementInput{
+		Sql: &sqlQuery,
+	}
+
+	_, err := r.providerData.ExecuteStatement(ctx, model.connection(), &statementOpts)
+
+	return err
+}
+
+// createHostAccount creates the account for the given host and grants it the configured privileges.
+func (r *MysqlUserMultiHostResource) createHostAccount(ctx context.Context, model *MysqlUserMultiHostResourceModel, host string) error {
+	createUserSqlQuery := fmt.Sprintf(
+		"CREATE USER IF NOT EXISTS '%s'@'%s' IDENTIFIED BY %s",
+		model.User.ValueString(),
+		host,
+		sqlStringLiteral(model.Password.ValueString()),
+	)
+
+	if err := r.executeStatement(ctx, model, createUserSqlQuery); err != nil {
+		return err
+	}
+
+	// The account may exist already (a drifted host which lost its privileges, or a create resumed after a failure),
+	// in which case `CREATE USER IF NOT EXISTS` left its password untouched
+	updateUserSqlQuery := fmt.Sprintf(
+		"ALTER USER '%s'@'%s' IDENTIFIED BY %s",
+		model.User.ValueString(),
+		host,
+		sqlStringLiteral(model.Password.ValueString()),
+	)
+
+	if err := r.executeStatement(ctx, model, updateUserSqlQuery); err != nil {
+		return err
+	}
+
+	return r.grantHostPrivileges(ctx, model, host)
+}
+
+// savePartialHosts saves the model into the state with only the accounts of the given hosts, after an operation
+// failed halfway.
+func (r *MysqlUserMultiHostResource) savePartialHosts(ctx context.Context, state *tfsdk.State, model MysqlUserMultiHostResourceModel, hosts []string) diag.Diagnostics {
+	hostsValue, diags := types.SetValueFrom(ctx, types.StringType, hosts)
+	if diags.HasError() {
+		return diags
+	}
+
+	model.Hosts = hostsValue
+	model.HostStatus = hostStatusMap(hosts, nil)
+
+	diags.Append(state.Set(ctx, &model)...)
+
+	return diags
+}
+
+// multiHostOperationTarget fingerprints the planned user, password, hosts and privileges.
+func multiHostOperationTarget(model MysqlUserMultiHostResourceModel) string {
+	values := []string{model.User.ValueString(), model.Password.ValueString()}
+	values = append(values, conv.StringSetToStrings(model.Hosts)...)
+
+	for _, grant := range model.Grants {
+		values = append(values, grant.Database.ValueString()+":"+strings.Join(conv.StringListToStrings(grant.Privileges), ","))
+	}
+
+	return operationTarget(values...)
+}
+
+// grantHostPrivileges grants the configured privileges to the account of the given host.
+func (r *MysqlUserMultiHostResource) grantHostPrivileges(ctx context.Context, model *MysqlUserMultiHostResourceModel, host string) error {
+	for _, grant := range model.Grants {
+		grantUserPrivilegesSqlQuery := fmt.Sprintf(
+			"GRANT %s ON %s.* TO '%s'@'%s'",
+			strings.Join(conv.StringListToStrings(grant.Privileges), ","),
+			grant.Database.ValueString(),
+			model.User.ValueString(),
+			host,
+		)
+
+		if err := r.executeStatement(ctx, model, grantUserPrivilegesSqlQuery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeHostGrants revokes the previously granted privileges from the account of the given host.
+func (r *MysqlUserMultiHostResource) revokeHostGrants(ctx context.Context, model *MysqlUserMultiHostResourceModel, host string) error {
+	revokeIfExists := r.providerData.supports(ctx, model.connection(), capabilityRevokeIfExists)
+
+	for _, grant := range model.Grants {
+		revokeUserPrivilegesSqlQuery := revokePrivilegesStatement(
+			[]string{"ALL PRIVILEGES"}, grant.Database.ValueString(), model.User.ValueString(), host, revokeIfExists,
+		)
+
+		err := r.executeStatement(ctx, model, revokeUserPrivilegesSqlQuery)
+
+		if err != nil && !isMysqlError(err, mysqlErrorNonexistingGrant) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasGrants reports whether the account of the given host holds any privilege besides `USAGE`.
+func (r *MysqlUserMultiHostResource) hasGrants(ctx context.Context, model *MysqlUserMultiHostResourceModel, host string) (bool, error) {
+	userGrantsSqlQuery := fmt.Sprintf("SHOW GRANTS FOR '%s'@'%s'", model.User.ValueString(), host)
+
+	userGrantsQueryStatementOpts := rdsdata.ExecuteStatementInput{
+		Sql: &userGrantsSqlQuery,
+	}
+
+	userGrantsSqlQueryResult, err := r.providerData.QueryStatement(ctx, model.connection(), &userGrantsQueryStatementOpts)
+
+	// The account was dropped since its host was listed
+	if isMysqlError(err, mysqlErrorNonexistingGrant) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// The `GRANT USAGE` privilege is always present so at least one record is returned
+	return len(userGrantsSqlQueryResult.Records) > 1, nil
+}
+
+// hostStatusMap returns the `host_status` attribute value, hosts missing from status are reported as present.
+func hostStatusMap(hosts []string, status map[string]string) types.Map {
+	elements := map[string]attr.Value{}
+
+	for _, host := range hosts {
+		hostStatus, ok := status[host]
+		if !ok {
+			hostStatus = mysqlUserHostStatusPresent
+		}
+
+		elements[host] = types.StringValue(hostStatus)
+	}
+
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// grantsEqual reports whether both grant block sets hold the same privileges.
+func grantsEqual(a []MysqlUserMultiHostGrantModel, b []MysqlUserMultiHostGrantModel) bool {
+	render := func(grants []MysqlUserMultiHostGrantModel) string {
+		rendered := make([]string, 0, len(grants))
+
+		for _, grant := range grants {
+			rendered = append(rendered, grant.Database.ValueString()+":"+strings.Join(conv.StringListToStrings(grant.Privileges), ","))
+		}
+
+		sort.Strings(rendered)
+
+		return strings.Join(rendered, ";")
+	}
+
+	return render(a) == render(b)
+}
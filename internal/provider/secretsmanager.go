@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// secretsManagerSecret identifies a (version of a) secret stored in AWS Secrets Manager.
+type secretsManagerSecret struct {
+	// Arn is the secret ARN
+	Arn string
+	// VersionId pins a specific secret version, it takes precedence over VersionStage
+	VersionId string
+	// VersionStage selects the secret version by its staging label (AWSCURRENT when empty)
+	VersionStage string
+	// JsonKey extracts a single key from a JSON secret instead of returning the whole secret string
+	JsonKey string
+}
+
+// getSecretString fetches the secret value from AWS Secrets Manager and returns it together with
+// the ID of the secret version it was read from.
+func getSecretString(ctx context.Context, client *secretsmanager.Client, secret secretsManagerSecret) (string, string, error) {
+	getSecretValueOpts := secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secret.Arn),
+	}
+
+	if secret.VersionId != "" {
+		getSecretValueOpts.VersionId = aws.String(secret.VersionId)
+	} else if secret.VersionStage != "" {
+		getSecretValueOpts.VersionStage = aws.String(secret.VersionStage)
+	}
+
+	secretValue, err := client.GetSecretValue(ctx, &getSecretValueOpts)
+	if err != nil {
+		return "", "", err
+	}
+
+	if secretValue.SecretString == nil {
+		return "", "", fmt.Errorf("secret %s does not contain a string value", secret.Arn)
+	}
+
+	value := *secretValue.SecretString
+
+	if secret.JsonKey != "" {
+		var secretFields map[string]interface{}
+
+		if err := json.Unmarshal([]byte(value), &secretFields); err != nil {
+			return "", "", fmt.Errorf("secret %s is not a valid JSON document: %w", secret.Arn, err)
+		}
+
+		field, ok := secretFields[secret.JsonKey].(string)
+		if !ok {
+			return "", "", fmt.Errorf("secret %s does not contain a string value for the %q key", secret.Arn, secret.JsonKey)
+		}
+
+		value = field
+	}
+
+	return value, aws.ToString(secretValue.VersionId), nil
+}
+
+// getSecretVersionId returns the ID of the secret version the secret value would be read from, without reading the
+// value itself.
+func getSecretVersionId(ctx context.Context, client *secretsmanager.Client, secret secretsManagerSecret) (string, error) {
+	if secret.VersionId != "" {
+		return secret.VersionId, nil
+	}
+
+	versionStage := secret.VersionStage
+	if versionStage == "" {
+		versionStage = "AWSCURRENT"
+	}
+
+	describeSecret, err := client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secret.Arn),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for versionId, stages := range describeSecret.VersionIdsToStages {
+		if slices.Contains(stages, versionStage) {
+			return versionId, nil
+		}
+	}
+
+	return "", fmt.Errorf("secret %s has no version with the %s staging label", secret.Arn, versionStage)
+}
+
+// rdsCredentialsSecret is the JSON secret structure used by the RDS MySQL secret rotation functions.
+type rdsCredentialsSecret struct {
+	Engine              string `json:"engine"`
+	Host                string `json:"host"`
+	Port                int32  `json:"port"`
+	Username            string `json:"username"`
+	Password            string `json:"password"`
+	DbClusterIdentifier string `json:"dbClusterIdentifier"`
+}
+
+// secretString returns the JSON representation of the credentials, as stored in the secret.
+func (c rdsCredentialsSecret) secretString() (string, error) {
+	secretJson, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secretJson), nil
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// The key holding the database username in RDS credentials secrets.
+const rdsCredentialsSecretUsernameKey = "username"
+
+// principalUsername returns the database username the provider authenticates with on the connection,
+// as stored in the connection secret. Usernames are cached for the provider lifetime.
+func (d *RdsDataProviderData) principalUsername(ctx context.Context, connection databaseConnection) (string, error) {
+	cacheKey := connection.AssumeRoleArn + "|" + connection.SecretArn
+
+	if username, ok := d.principalUsernames.Load(cacheKey); ok {
+		return username.(string), nil
+	}
+
+	client, err := d.SecretsManagerClient(ctx, connection, connection.SecretArn)
+	if err != nil {
+		return "", err
+	}
+
+	username, _, err := getSecretString(ctx, client, secretsManagerSecret{
+		Arn:     connection.SecretArn,
+		JsonKey: rdsCredentialsSecretUsernameKey,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	d.principalUsernames.Store(cacheKey, username)
+
+	return username, nil
+}
+
+// checkSelfManagement prevents changes to the database user the provider authenticates with: rotating its password
+// or dropping it would cut the provider (and the RDS Data API) off the database. The database secret only stores the
+// user name, so the accounts of every host of the user are protected. It fails closed when the secret can't be read.
+func (d *RdsDataProviderData) checkSelfManagement(ctx context.Context, connection databaseConnection, user string, attributePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if d.Policy != nil && d.Policy.AllowSelfManagement {
+		return diags
+	}
+
+	// The connection is not known yet
+	if connection.SecretArn == "" || user == "" {
+		return diags
+	}
+
+	username, err := d.principalUsername(ctx, connection)
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath,
+			"Unable to verify the provider database user",
+			fmt.Sprintf(
+				"The database username could not be read from the %q key of the %s secret, so the %q MySQL user may be "+
+					"the user the provider authenticates with: %s. Grant the provider access to the secret, or set "+
+					"`allow_self_management = true` in the provider `policy` block to skip the check",
+				rdsCredentialsSecretUsernameKey, connection.SecretArn, user, err,
+			),
+		)
+
+		return diags
+	}
+
+	if username == user {
+		diags.AddAttributeError(
+			attributePath,
+			"Provider database user cannot be managed",
+			fmt.Sprintf(
+				"The %q MySQL user is the user the provider authenticates with (as stored in the %s secret): changing its "+
+					"password, privileges or dropping it would cut the provider and the RDS Data API off the database. "+
+					"Set `allow_self_management = true` in the provider `policy` block to manage it anyway",
+				user, connection.SecretArn,
+			),
+		)
+	}
+
+	return diags
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// redactedSQLValue replaces the credentials of redacted statements
+	redactedSQLValue = "'***'"
+	// sqlPreviewUnknown replaces the values only known on apply in SQL previews
+	sqlPreviewUnknown = "(known after apply)"
+)
+
+// Matches the credentials of `IDENTIFIED BY '...'` and `IDENTIFIED WITH plugin BY|AS '...'` clauses
+// (quoted strings or hex literals).
+var identifiedClauseRegex = regexp.MustCompile(
+	`(?i)(\bIDENTIFIED\s+(?:WITH\s+\S+\s+)?(?:BY|AS)\s+)(?:'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"|0x[0-9a-f]+|x'[0-9a-f]*')`,
+)
+
+// redactSQL replaces the passwords and authentication strings of the SQL statement with `'***'`.
+func redactSQL(sql string) string {
+	return identifiedClauseRegex.ReplaceAllString(sql, "${1}"+redactedSQLValue)
+}
+
+// Matches the statement fragment MySQL quotes in syntax errors (`near '...' at line 1`), which may start in the
+// middle of a credential.
+var mysqlErrorNearFragmentRegex = regexp.MustCompile(`(?s)(\bnear )'.*'( at line \d+)`)
+
+// redactErrorMessage redacts the credentials of the statement quoted in an error message: the statement fragments of
+// MySQL syntax errors are dropped altogether.
+func redactErrorMessage(message string) string {
+	return mysqlErrorNearFragmentRegex.ReplaceAllString(redactSQL(message), "${1}"+redactedSQLValue+"${2}")
+}
+
+// previewString returns the value to render in SQL previews, values unknown at plan time are rendered as placeholders.
+func previewString(value interface {
+	IsUnknown() bool
+	ValueString() string
+}) string {
+	if value.IsUnknown() {
+		return sqlPreviewUnknown
+	}
+
+	return value.ValueString()
+}
+
+// previewStrings returns the list elements to render in SQL previews.
+func previewStrings(list types.List) []string {
+	if list.IsUnknown() {
+		return []string{sqlPreviewUnknown}
+	}
+
+	values := make([]string, 0, len(list.Elements()))
+
+	for _, element := range list.Elements() {
+		if value, ok := element.(types.String); ok {
+			values = append(values, previewString(value))
+		}
+	}
+
+	return values
+}
+
+// addSQLPreview adds a plan warning listing the statements the resource will run on apply, with redacted credentials.
+func addSQLPreview(diags *diag.Diagnostics, statements []string) {
+	var rendered []string
+
+	for _, statement := range statements {
+		if statement != "" {
+			rendered = append(rendered, redactSQL(statement)+";")
+		}
+	}
+
+	if len(rendered) == 0 {
+		return
+	}
+
+	diags.AddWarning(
+		"Planned SQL statements",
+		"The following statements will be run on apply (credentials are redacted):\n\n"+strings.Join(rendered, "\n"),
+	)
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestRedactSQL(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"CREATE USER 'app'@'%' IDENTIFIED BY 'secret'", "CREATE USER 'app'@'%' IDENTIFIED BY '***'"},
+		{"ALTER USER 'app'@'%' identified by 'it''s \\' secret' RETAIN CURRENT PASSWORD", "ALTER USER 'app'@'%' identified by '***' RETAIN CURRENT PASSWORD"},
+		{
+			"CREATE USER 'app'@'%' IDENTIFIED WITH caching_sha2_password AS 0x2441243030352400",
+			"CREATE USER 'app'@'%' IDENTIFIED WITH caching_sha2_password AS '***'",
+		},
+		{"GRANT SELECT ON app.* TO 'app'@'%'", "GRANT SELECT ON app.* TO 'app'@'%'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			if redacted := redactSQL(test.sql); redacted != test.expected {
+				t.Errorf("redactSQL(%q) = %q, expected %q", test.sql, redacted, test.expected)
+			}
+		})
+	}
+}
+
+func TestRedactErrorMessage(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{
+			"Error 1064 (42000): You have an error in your SQL syntax; check the manual that corresponds to your MySQL " +
+				"server version for the right syntax to use near 'cret' RETAIN CURRENT PASSWORDS' at line 1",
+			"Error 1064 (42000): You have an error in your SQL syntax; check the manual that corresponds to your MySQL " +
+				"server version for the right syntax to use near '***' at line 1",
+		},
+		{
+			"Operation CREATE USER failed for 'app'@'%' IDENTIFIED BY 'secret'",
+			"Operation CREATE USER failed for 'app'@'%' IDENTIFIED BY '***'",
+		},
+		{
+			"Access denied for user 'admin'@'%' to database 'app'",
+			"Access denied for user 'admin'@'%' to database 'app'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			if redacted := redactErrorMessage(test.message); redacted != test.expected {
+				t.Errorf("redactErrorMessage(%q) = %q, expected %q", test.message, redacted, test.expected)
+			}
+		})
+	}
+}
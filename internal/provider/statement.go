@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"strings"
+	"unicode"
+)
+
+// statementKind classifies SQL statements by their side effects.
+type statementKind int
+
+const (
+	// statementKindWrite changes the database (DDL, DML, account management), unknown statements are writes
+	statementKindWrite statementKind = iota
+	// statementKindRead only reads data (SELECT, SHOW, ...)
+	statementKindRead
+)
+
+func (k statementKind) String() string {
+	if k == statementKindRead {
+		return "read"
+	}
+
+	return "write"
+}
+
+// The statements without side effects, anything else is considered a write.
+var readStatementKeywords = []string{"SELECT", "SHOW", "DESCRIBE", "DESC", "EXPLAIN"}
+
+// classifyStatement returns the kind of the SQL statement. It fails closed: multiple statements, `SELECT ... INTO`
+// and statements it doesn't recognize are writes.
+func classifyStatement(sql string) statementKind {
+	tokens := statementTokens(sql)
+
+	if len(tokens) == 0 {
+		return statementKindWrite
+	}
+
+	isRead := false
+	for _, keyword := range readStatementKeywords {
+		if tokens[0] == keyword {
+			isRead = true
+			break
+		}
+	}
+
+	if !isRead {
+		return statementKindWrite
+	}
+
+	for i, token := range tokens {
+		switch {
+		// a second statement follows
+		case token == ";" && i < len(tokens)-1:
+			return statementKindWrite
+		// SELECT ... INTO OUTFILE / DUMPFILE / @variable
+		case token == "INTO":
+			return statementKindWrite
+		}
+	}
+
+	return statementKindRead
+}
+
+// statementTokens splits the SQL statement into upper case keywords and `;` separators, skipping comments,
+// string literals and quoted identifiers. MySQL runs the content of executable comments (`/*! ... */`,
+// `/*!80000 ... */`), which is tokenized as statement text.
+func statementTokens(sql string) []string {
+	var tokens []string
+	var word strings.Builder
+
+	executableComment := false
+
+	flushWord := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, strings.ToUpper(word.String()))
+			word.Reset()
+		}
+	}
+
+	runes := []rune(sql)
+
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+
+		switch {
+		// `-- comment` and `# comment`
+		case char == '#' || (char == '-' && i+2 < len(runes) && runes[i+1] == '-' && unicode.IsSpace(runes[i+2])):
+			flushWord()
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		// `/*! executable comment */`, optionally with the minimum server version
+		case char == '/' && i+2 < len(runes) && runes[i+1] == '*' && runes[i+2] == '!':
+			flushWord()
+			executableComment = true
+			i += 2
+			for i+1 < len(runes) && unicode.IsDigit(runes[i+1]) {
+				i++
+			}
+		case executableComment && char == '*' && i+1 < len(runes) && runes[i+1] == '/':
+			flushWord()
+			executableComment = false
+			i++
+		// `/* comment */`
+		case char == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			flushWord()
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i++
+		// 'string', "string" and `identifier`
+		case char == '\'' || char == '"' || char == '`':
+			flushWord()
+			for i++; i < len(runes) && runes[i] != char; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+		case char == ';':
+			flushWord()
+			tokens = append(tokens, ";")
+		case unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_':
+			word.WriteRune(char)
+		default:
+			flushWord()
+		}
+	}
+
+	flushWord()
+
+	return tokens
+}
+
+// Escapes the characters of SQL string literals: backslashes, quotes and NUL bytes.
+var sqlStringLiteralReplacer = strings.NewReplacer(`\`, `\\`, `'`, `''`, "\x00", `\0`)
+
+// sqlStringLiteral returns the value as a single quoted SQL string literal, so values read outside the configuration
+// (e.g. secrets) can be embedded in statements. It assumes the default SQL mode (without `NO_BACKSLASH_ESCAPES`).
+func sqlStringLiteral(value string) string {
+	return "'" + sqlStringLiteralReplacer.Replace(value) + "'"
+}
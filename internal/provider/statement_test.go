@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClassifyStatement(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected statementKind
+	}{
+		{"SELECT 1", statementKindRead},
+		{"select user, host from mysql.user", statementKindRead},
+		{"  SHOW GRANTS FOR CURRENT_USER()", statementKindRead},
+		{"SHOW PRIVILEGES;", statementKindRead},
+		{"DESCRIBE mysql.user", statementKindRead},
+		{"EXPLAIN SELECT 1", statementKindRead},
+		{"-- comment\nSELECT 1", statementKindRead},
+		{"/* comment */ SELECT 1", statementKindRead},
+		{"SELECT ';DROP USER x' FROM dual", statementKindRead},
+		{"SELECT `into` FROM t", statementKindRead},
+		{"SELECT 1 -- ; DROP USER x", statementKindRead},
+		{"", statementKindWrite},
+		{"   ", statementKindWrite},
+		{"-- only a comment", statementKindWrite},
+		{"CREATE USER 'x'@'%'", statementKindWrite},
+		{"GRANT SELECT ON db.* TO 'x'@'%'", statementKindWrite},
+		{"SELECT 1; DROP USER 'x'@'%'", statementKindWrite},
+		{"SELECT * FROM t INTO OUTFILE '/tmp/t'", statementKindWrite},
+		{"SELECT 1 INTO @variable", statementKindWrite},
+		{"/* SELECT */ DROP USER 'x'@'%'", statementKindWrite},
+		{"/*! DROP USER 'x'@'%' */ SELECT 1", statementKindWrite},
+		{"SELECT 1 /*!80000 ; DROP USER 'x'@'%' */", statementKindWrite},
+		{"SELECT 1 /*!INTO @variable */", statementKindWrite},
+		{"# SELECT\nDROP USER 'x'@'%'", statementKindWrite},
+	}
+
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			if kind := classifyStatement(test.sql); kind != test.expected {
+				t.Errorf("classifyStatement(%q) = %s, expected %s", test.sql, kind, test.expected)
+			}
+		})
+	}
+}
+
+func TestStatementTokens(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected []string
+	}{
+		{"select user from mysql.user;", []string{"SELECT", "USER", "FROM", "MYSQL", "USER", ";"}},
+		{"SELECT 'it''s' AS `a b`", []string{"SELECT", "AS"}},
+		{`SELECT "a \" b", 1`, []string{"SELECT", "1"}},
+		{"SELECT 1 # comment\n, 2", []string{"SELECT", "1", "2"}},
+		// `--` only starts a comment when followed by a whitespace
+		{"SELECT 1 --comment", []string{"SELECT", "1", "COMMENT"}},
+		{"SELECT 1 -- comment", []string{"SELECT", "1"}},
+		{"SELECT /* a */ 1 /*!80023 , 2 */", []string{"SELECT", "1", "2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			if tokens := statementTokens(test.sql); !slices.Equal(tokens, test.expected) {
+				t.Errorf("statementTokens(%q) = %q, expected %q", test.sql, tokens, test.expected)
+			}
+		})
+	}
+}
+
+func TestSqlStringLiteral(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"password", `'password'`},
+		{"", `''`},
+		{"it's", `'it''s'`},
+		{`back\slash`, `'back\\slash'`},
+		{`\'; DROP USER x; --`, `'\\''; DROP USER x; --'`},
+		{"nul\x00byte", `'nul\0byte'`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if literal := sqlStringLiteral(test.value); literal != test.expected {
+				t.Errorf("sqlStringLiteral(%q) = %s, expected %s", test.value, literal, test.expected)
+			}
+		})
+	}
+}
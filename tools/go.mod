@@ -1,6 +1,6 @@
 module terraform-provider-awsrdsdata
 
-go 1.19
+go 1.22.0
 
 require (
 	github.com/hashicorp/copywrite v0.16.6